@@ -5,66 +5,274 @@ package api
 
 import (
 	"Groupie-Tracker/models"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
-	"time"
+	"strings"
+	"sync"
 )
 
 const (
-	baseURL         = "https://groupietrackers.herokuapp.com/api"
-	artistsEndpoint = baseURL + "/artists"
+	artistsPath   = "/artists"
+	locationsPath = "/locations"
+	datesPath     = "/dates"
+	relationPath  = "/relation"
 )
 
-func fetchAPI(url string, target interface{}) error {
-	// Créer client HTTP avec timeout
-	client := http.Client{
-		Timeout: 10 * time.Second,
+// GetArtists récupère les artistes via le client par défaut
+func GetArtists(ctx context.Context) (*ArtistsResult, error) {
+	return DefaultClient.GetArtists(ctx)
+}
+
+// fetchAPI télécharge url et décode le JSON dans target.
+// Une réponse déjà en cache est revalidée par requête conditionnelle : un 304 réutilise son corps.
+func (c *Client) fetchAPI(ctx context.Context, url string, target interface{}) error {
+	url = c.resolve(url)
+
+	// Préparer la requête
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	cached := c.responses.get(url)
+	if cached != nil {
+		cached.setValidators(req)
 	}
 
-	// Faire requête GET
-	resp, err := client.Get(url)
+	// Faire requête GET, avec nouvelles tentatives sur les erreurs transitoires
+	resp, err := c.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
+	// Le serveur confirme que la copie en cache est à jour
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		c.responses.hits.Add(1)
+		return json.Unmarshal(cached.Body, target)
+	}
+
 	// Vérifier status HTTP
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("HTTP error: %s", resp.Status)
 	}
 
-	// Décoder JSON dans target
-	err = json.NewDecoder(resp.Body).Decode(target)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
+	c.responses.misses.Add(1)
+
+	// Décoder JSON dans target, puis mémoriser la réponse valide
+	if err := json.Unmarshal(body, target); err != nil {
+		return err
+	}
+	c.responses.store(url, resp.Header, body)
 	return nil
 }
 
-func GetArtists() ([]models.Artist, error) {
+// ArtistError décrit l'échec du chargement des détails d'un artiste
+type ArtistError struct {
+	Artist models.Artist
+	Err    error
+}
+
+func (e ArtistError) Error() string {
+	return fmt.Sprintf("artist %d (%s): %v", e.Artist.Id, e.Artist.Name, e.Err)
+}
+
+func (e ArtistError) Unwrap() error {
+	return e.Err
+}
+
+// ArtistsResult regroupe les artistes chargés avec succès et les échecs par artiste
+type ArtistsResult struct {
+	Artists []models.Artist
+	Errors  []ArtistError
+}
+
+// GetArtists récupère les artistes puis leur associe lieux, dates et relations.
+// Les index globaux (/locations, /dates, /relation) sont chargés en une requête chacun ;
+// si un index est indisponible, on retombe sur les URLs propres à chaque artiste,
+// interrogées en parallèle par un pool borné de workers. Seul l'échec de la liste
+// des artistes est bloquant : les échecs individuels sont rapportés dans Errors.
+func (c *Client) GetArtists(ctx context.Context) (*ArtistsResult, error) {
 	var artists []models.Artist
-	if err := fetchAPI(artistsEndpoint, &artists); err != nil {
+	if err := c.fetchAPI(ctx, c.endpoint(artistsPath), &artists); err != nil {
 		return nil, err
 	}
 
+	// Une erreur d'index n'est pas bloquante : la map reste vide et chaque artiste passe par le repli
+	var (
+		wg            sync.WaitGroup
+		locationsByID map[int][]string
+		datesByID     map[int][]string
+		relationsByID map[int]map[string][]string
+	)
+	wg.Add(3)
+	go func() { defer wg.Done(); locationsByID, _ = c.getLocationsIndex(ctx) }()
+	go func() { defer wg.Done(); datesByID, _ = c.getDatesIndex(ctx) }()
+	go func() { defer wg.Done(); relationsByID, _ = c.getRelationsIndex(ctx) }()
+	wg.Wait()
+
+	errs := make([]error, len(artists))
+	jobs := make(chan int)
+	for w := 0; w < c.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = c.fillArtistDetails(ctx, &artists[i], locationsByID, datesByID, relationsByID)
+			}
+		}()
+	}
 	for i := range artists {
-		locs, err := getArtistLocations(artists[i].LocationsURL)
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	result := &ArtistsResult{}
+	for i, a := range artists {
+		if errs[i] != nil {
+			result.Errors = append(result.Errors, ArtistError{Artist: a, Err: errs[i]})
+			continue
+		}
+		result.Artists = append(result.Artists, a)
+	}
+	return result, nil
+}
+
+// fillArtistDetails complète un artiste depuis les index, ou via ses propres URLs à défaut
+func (c *Client) fillArtistDetails(ctx context.Context, a *models.Artist, locationsByID, datesByID map[int][]string, relationsByID map[int]map[string][]string) error {
+	if locs, ok := locationsByID[a.Id]; ok {
+		a.Locations = locs
+	} else {
+		locs, err := c.getArtistLocations(ctx, a.LocationsURL)
 		if err != nil {
-			return nil, fmt.Errorf("fetch locations for artist %d: %w", artists[i].Id, err)
+			return fmt.Errorf("fetch locations: %w", err)
 		}
-		artists[i].Locations = locs
+		a.Locations = locs
+	}
+
+	if dates, ok := datesByID[a.Id]; ok {
+		a.ConcertDates = dates
+	} else {
+		dates, err := c.getArtistDates(ctx, a.ConcertDatesURL)
+		if err != nil {
+			return fmt.Errorf("fetch dates: %w", err)
+		}
+		a.ConcertDates = dates
+	}
+
+	if relations, ok := relationsByID[a.Id]; ok {
+		a.Relations = relations
+	} else {
+		relations, err := c.getArtistRelations(ctx, a.RelationsURL)
+		if err != nil {
+			return fmt.Errorf("fetch relations: %w", err)
+		}
+		a.Relations = relations
+	}
+
+	return nil
+}
+
+// getLocationsIndex charge l'index global des lieux, indexé par id d'artiste
+func (c *Client) getLocationsIndex(ctx context.Context) (map[int][]string, error) {
+	var payload struct {
+		Index []struct {
+			Id        int      `json:"id"`
+			Locations []string `json:"locations"`
+		} `json:"index"`
+	}
+	if err := c.fetchAPI(ctx, c.endpoint(locationsPath), &payload); err != nil {
+		return nil, err
+	}
+	byID := make(map[int][]string, len(payload.Index))
+	for _, entry := range payload.Index {
+		byID[entry.Id] = entry.Locations
+	}
+	return byID, nil
+}
+
+// getDatesIndex charge l'index global des dates, indexé par id d'artiste
+func (c *Client) getDatesIndex(ctx context.Context) (map[int][]string, error) {
+	var payload struct {
+		Index []models.Dates `json:"index"`
+	}
+	if err := c.fetchAPI(ctx, c.endpoint(datesPath), &payload); err != nil {
+		return nil, err
 	}
+	byID := make(map[int][]string, len(payload.Index))
+	for _, entry := range payload.Index {
+		byID[entry.Id] = cleanDates(entry.Dates)
+	}
+	return byID, nil
+}
 
-	return artists, nil
+// getRelationsIndex charge l'index global des relations, indexé par id d'artiste
+func (c *Client) getRelationsIndex(ctx context.Context) (map[int]map[string][]string, error) {
+	var payload struct {
+		Index []models.Relation `json:"index"`
+	}
+	if err := c.fetchAPI(ctx, c.endpoint(relationPath), &payload); err != nil {
+		return nil, err
+	}
+	byID := make(map[int]map[string][]string, len(payload.Index))
+	for _, entry := range payload.Index {
+		byID[entry.Id] = cleanRelations(entry.DatesLocations)
+	}
+	return byID, nil
 }
 
-func getArtistLocations(url string) ([]string, error) {
+func (c *Client) getArtistLocations(ctx context.Context, url string) ([]string, error) {
 	var payload struct {
 		Locations []string `json:"locations"`
 	}
-	if err := fetchAPI(url, &payload); err != nil {
+	if err := c.fetchAPI(ctx, url, &payload); err != nil {
 		return nil, err
 	}
 	return payload.Locations, nil
 }
+
+// getArtistDates récupère les dates de concert d'un artiste
+func (c *Client) getArtistDates(ctx context.Context, url string) ([]string, error) {
+	var payload models.Dates
+	if err := c.fetchAPI(ctx, url, &payload); err != nil {
+		return nil, err
+	}
+	return cleanDates(payload.Dates), nil
+}
+
+// getArtistRelations récupère l'association lieu → dates d'un artiste
+func (c *Client) getArtistRelations(ctx context.Context, url string) (map[string][]string, error) {
+	var payload models.Relation
+	if err := c.fetchAPI(ctx, url, &payload); err != nil {
+		return nil, err
+	}
+	return cleanRelations(payload.DatesLocations), nil
+}
+
+// cleanRelations applique cleanDates à chaque lieu de la relation
+func cleanRelations(datesLocations map[string][]string) map[string][]string {
+	relations := make(map[string][]string, len(datesLocations))
+	for loc, dates := range datesLocations {
+		relations[loc] = cleanDates(dates)
+	}
+	return relations
+}
+
+// cleanDates retire le préfixe "*" que l'API ajoute à certaines dates
+func cleanDates(dates []string) []string {
+	cleaned := make([]string, 0, len(dates))
+	for _, d := range dates {
+		d = strings.TrimSpace(strings.TrimPrefix(d, "*"))
+		if d != "" {
+			cleaned = append(cleaned, d)
+		}
+	}
+	return cleaned
+}
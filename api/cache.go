@@ -0,0 +1,177 @@
+// Package api - cache.go persiste le jeu de données des artistes sur disque.
+// Le fichier est stocké dans le répertoire de cache de l'utilisateur avec sa date de récupération,
+// ce qui permet de démarrer instantanément, et même hors ligne, avant de rafraîchir en arrière-plan.
+package api
+
+import (
+	"Groupie-Tracker/models"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL est la durée pendant laquelle le jeu de données en cache est considéré à jour
+const DefaultCacheTTL = 24 * time.Hour
+
+// errCachedFailure remplace, au rechargement du cache, l'erreur d'origine qui n'est pas sérialisée
+var errCachedFailure = errors.New("details unavailable when the dataset was cached")
+
+// DatasetCache lit et écrit le jeu de données des artistes dans un fichier JSON
+type DatasetCache struct {
+	path string
+	ttl  time.Duration
+}
+
+// CachedDataset est le contenu du fichier de cache
+type CachedDataset struct {
+	FetchedAt time.Time      `json:"fetchedAt"`
+	Artists   []cachedArtist `json:"artists"`
+	Failed    []int          `json:"failed,omitempty"`
+}
+
+// cachedArtist sérialise aussi les champs que l'API ne fournit pas directement dans /artists
+type cachedArtist struct {
+	models.Artist
+	CachedLocations []string            `json:"cachedLocations"`
+	CachedDates     []string            `json:"cachedDates"`
+	CachedRelations map[string][]string `json:"cachedRelations"`
+}
+
+// AppCacheDir renvoie le répertoire de cache de l'application, créé si besoin
+func AppCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(base, "groupie-tracker")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// NewDatasetCache crée un cache à l'emplacement path ; une ttl nulle utilise DefaultCacheTTL
+func NewDatasetCache(path string, ttl time.Duration) *DatasetCache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &DatasetCache{path: path, ttl: ttl}
+}
+
+// DefaultDatasetCache crée un cache dans le répertoire de cache de l'utilisateur
+func DefaultDatasetCache() (*DatasetCache, error) {
+	dir, err := AppCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewDatasetCache(filepath.Join(dir, "artists.json"), DefaultCacheTTL), nil
+}
+
+// Load lit le jeu de données en cache
+func (c *DatasetCache) Load() (*CachedDataset, error) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+	var dataset CachedDataset
+	if err := json.Unmarshal(data, &dataset); err != nil {
+		return nil, err
+	}
+	return &dataset, nil
+}
+
+// Save écrit le résultat d'un chargement dans le cache, de manière atomique
+func (c *DatasetCache) Save(result *ArtistsResult) error {
+	dataset := CachedDataset{FetchedAt: time.Now()}
+	for _, a := range result.Artists {
+		dataset.Artists = append(dataset.Artists, newCachedArtist(a))
+	}
+	for _, e := range result.Errors {
+		dataset.Artists = append(dataset.Artists, newCachedArtist(e.Artist))
+		dataset.Failed = append(dataset.Failed, e.Artist.Id)
+	}
+
+	data, err := json.Marshal(dataset)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), "artists-*.json.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// RestoreFailed reprend du cache les détails des artistes en échec dans result
+// qui y étaient complets : un échec transitoire lors d'un rafraîchissement ne doit pas
+// effacer des données hors ligne valides. Sans cache lisible, result est renvoyé tel quel.
+func (c *DatasetCache) RestoreFailed(result *ArtistsResult) *ArtistsResult {
+	if len(result.Errors) == 0 {
+		return result
+	}
+	previous, err := c.Load()
+	if err != nil {
+		return result
+	}
+	good := make(map[int]models.Artist)
+	for _, a := range previous.Result().Artists {
+		good[a.Id] = a
+	}
+
+	restored := &ArtistsResult{Artists: append([]models.Artist(nil), result.Artists...)}
+	for _, e := range result.Errors {
+		if a, ok := good[e.Artist.Id]; ok {
+			restored.Artists = append(restored.Artists, a)
+			continue
+		}
+		restored.Errors = append(restored.Errors, e)
+	}
+	return restored
+}
+
+// Fresh indique si le jeu de données est encore dans la ttl du cache
+func (c *DatasetCache) Fresh(dataset *CachedDataset) bool {
+	return time.Since(dataset.FetchedAt) < c.ttl
+}
+
+// Result reconstruit un ArtistsResult à partir du cache
+func (d *CachedDataset) Result() *ArtistsResult {
+	failed := make(map[int]bool, len(d.Failed))
+	for _, id := range d.Failed {
+		failed[id] = true
+	}
+
+	result := &ArtistsResult{}
+	for _, ca := range d.Artists {
+		a := ca.Artist
+		a.Locations = ca.CachedLocations
+		a.ConcertDates = ca.CachedDates
+		a.Relations = ca.CachedRelations
+		if failed[a.Id] {
+			result.Errors = append(result.Errors, ArtistError{Artist: a, Err: errCachedFailure})
+			continue
+		}
+		result.Artists = append(result.Artists, a)
+	}
+	return result
+}
+
+func newCachedArtist(a models.Artist) cachedArtist {
+	return cachedArtist{
+		Artist:          a,
+		CachedLocations: a.Locations,
+		CachedDates:     a.ConcertDates,
+		CachedRelations: a.Relations,
+	}
+}
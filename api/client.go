@@ -0,0 +1,139 @@
+// Package api - client.go définit le client HTTP injectable utilisé par tous les appels à l'API.
+// L'URL de base, le timeout, le transport et le User-Agent sont configurables via des options,
+// ce qui permet de pointer l'application vers un miroir local ou un httptest.Server.
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL est l'URL publique de l'API Groupie Trackers
+	DefaultBaseURL = "https://groupietrackers.herokuapp.com/api"
+	// DefaultTimeout est le délai maximal d'une requête HTTP
+	DefaultTimeout = 10 * time.Second
+	// DefaultUserAgent identifie l'application auprès des serveurs distants, comme l'exigent
+	// les politiques d'usage des tuiles OSM et de Nominatim
+	DefaultUserAgent = "GroupieTracker/1.0 (+https://github.com/Saraht25/Groupie-Tracker)"
+	// DefaultConcurrency borne le nombre de requêtes par artiste lancées en parallèle
+	DefaultConcurrency = 8
+)
+
+// Client porte la configuration HTTP partagée par tous les appels à l'API
+type Client struct {
+	baseURL     string
+	userAgent   string
+	concurrency int
+	retry       RetryPolicy
+	responses   *responseCache
+	httpClient  *http.Client
+}
+
+// Option modifie la configuration d'un Client
+type Option func(*Client)
+
+// WithBaseURL remplace l'URL de base de l'API (ex: "http://localhost:8080/api")
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithTimeout fixe le délai maximal d'une requête
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithTransport remplace le transport HTTP (utile pour les tests ou un proxy)
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+// WithUserAgent remplace l'en-tête User-Agent envoyé à chaque requête
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithConcurrency borne le nombre de requêtes par artiste lancées en parallèle
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithRetryPolicy remplace la politique de nouvelle tentative (NoRetry pour la désactiver)
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithResponseCacheDir conserve aussi sur disque, dans dir, les réponses revalidables
+func WithResponseCacheDir(dir string) Option {
+	return func(c *Client) {
+		c.responses = newResponseCache(dir)
+	}
+}
+
+// NewClient crée un client avec les valeurs par défaut, modifiées par les options
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		baseURL:     DefaultBaseURL,
+		userAgent:   DefaultUserAgent,
+		concurrency: DefaultConcurrency,
+		retry:       DefaultRetryPolicy,
+		responses:   newResponseCache(""),
+		httpClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// DefaultClient est le client utilisé par les fonctions du package
+var DefaultClient = NewClient()
+
+// BaseURL renvoie l'URL de base configurée
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// CacheStats renvoie le nombre de réponses revalidées (304) et téléchargées depuis la création du client
+func (c *Client) CacheStats() CacheStats {
+	return c.responses.stats()
+}
+
+// Do envoie req avec la politique de nouvelle tentative du client.
+// Le User-Agent par défaut est ajouté si req n'en définit pas ; le contexte de req est respecté.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	return c.retry.Do(req.Context(), c.httpClient, req)
+}
+
+// endpoint construit l'URL complète d'un chemin de l'API
+func (c *Client) endpoint(path string) string {
+	return c.baseURL + path
+}
+
+// resolve réécrit les URLs absolues renvoyées par l'API publique vers l'URL de base configurée
+func (c *Client) resolve(url string) string {
+	if c.baseURL != DefaultBaseURL && strings.HasPrefix(url, DefaultBaseURL) {
+		return c.baseURL + strings.TrimPrefix(url, DefaultBaseURL)
+	}
+	return url
+}
@@ -0,0 +1,155 @@
+package api_test
+
+import (
+	"Groupie-Tracker/api"
+	"Groupie-Tracker/mockapi"
+	"Groupie-Tracker/models"
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newMockClient démarre le serveur simulé avec opts et renvoie un client sans nouvelle tentative qui l'interroge
+func newMockClient(t *testing.T, opts ...mockapi.Option) *api.Client {
+	t.Helper()
+	srv, err := mockapi.NewServer(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Close)
+	return api.NewClient(api.WithBaseURL(srv.URL+"/api"), api.WithRetryPolicy(api.NoRetry))
+}
+
+// byID indexe les artistes par identifiant
+func byID(artists []models.Artist) map[int]models.Artist {
+	m := make(map[int]models.Artist, len(artists))
+	for _, a := range artists {
+		m[a.Id] = a
+	}
+	return m
+}
+
+func TestGetArtistsFromIndexes(t *testing.T) {
+	result, err := newMockClient(t).GetArtists(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Errors = %v, want none", result.Errors)
+	}
+	if len(result.Artists) != 5 {
+		t.Fatalf("got %d artists, want 5", len(result.Artists))
+	}
+	for _, a := range result.Artists {
+		if len(a.Locations) == 0 || len(a.ConcertDates) == 0 || len(a.Relations) == 0 {
+			t.Errorf("artist %d (%s) has missing details", a.Id, a.Name)
+		}
+	}
+}
+
+func TestGetArtistsPerArtistFallback(t *testing.T) {
+	want, err := newMockClient(t).GetArtists(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sans aucun index, chaque artiste passe par ses propres URLs
+	client := newMockClient(t, mockapi.WithFailingPaths("/api/locations", "/api/dates", "/api/relation"))
+	got, err := client.GetArtists(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Errors) != 0 {
+		t.Fatalf("Errors = %v, want none", got.Errors)
+	}
+
+	wantByID := byID(want.Artists)
+	for _, a := range got.Artists {
+		w := wantByID[a.Id]
+		if !reflect.DeepEqual(a.Locations, w.Locations) ||
+			!reflect.DeepEqual(a.ConcertDates, w.ConcertDates) ||
+			!reflect.DeepEqual(a.Relations, w.Relations) {
+			t.Errorf("artist %d: fallback details differ from the index", a.Id)
+		}
+	}
+}
+
+func TestGetArtistsPartialFailure(t *testing.T) {
+	client := newMockClient(t, mockapi.WithFailingPaths("/api/locations", "/api/locations/3"))
+	result, err := client.GetArtists(context.Background())
+	if err != nil {
+		t.Fatalf("a per-artist failure must not fail the whole load: %v", err)
+	}
+
+	if len(result.Artists) != 4 {
+		t.Errorf("got %d artists, want 4", len(result.Artists))
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1", len(result.Errors))
+	}
+	var artistErr api.ArtistError
+	if !errors.As(result.Errors[0], &artistErr) || artistErr.Artist.Id != 3 {
+		t.Errorf("error = %v, want an ArtistError for artist 3", result.Errors[0])
+	}
+}
+
+func TestRevalidationCountsHits(t *testing.T) {
+	client := newMockClient(t)
+	ctx := context.Background()
+
+	if _, err := client.GetArtists(ctx); err != nil {
+		t.Fatal(err)
+	}
+	first := client.CacheStats()
+	if first.Hits != 0 || first.Misses == 0 {
+		t.Fatalf("after first load: %+v, want only misses", first)
+	}
+
+	if _, err := client.GetArtists(ctx); err != nil {
+		t.Fatal(err)
+	}
+	second := client.CacheStats()
+	if second.Misses != first.Misses {
+		t.Errorf("misses = %d after revalidation, want %d", second.Misses, first.Misses)
+	}
+	// /artists et les trois index sont revalidés par 304
+	if second.Hits != 4 {
+		t.Errorf("hits = %d, want 4", second.Hits)
+	}
+}
+
+func TestDatasetCacheRestoreFailed(t *testing.T) {
+	ctx := context.Background()
+	cache := api.NewDatasetCache(filepath.Join(t.TempDir(), "artists.json"), 0)
+
+	partial, err := newMockClient(t, mockapi.WithFailingPaths("/api/locations", "/api/locations/3")).GetArtists(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sans cache sur disque, les échecs sont conservés
+	if restored := cache.RestoreFailed(partial); len(restored.Errors) != 1 {
+		t.Fatalf("without a cache: %d errors, want 1", len(restored.Errors))
+	}
+
+	full, err := newMockClient(t).GetArtists(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Save(full); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := cache.RestoreFailed(partial)
+	if len(restored.Errors) != 0 {
+		t.Fatalf("Errors = %v, want the failed artist restored from the cache", restored.Errors)
+	}
+	if len(restored.Artists) != 5 {
+		t.Fatalf("got %d artists, want 5", len(restored.Artists))
+	}
+	if got, want := byID(restored.Artists)[3].Locations, byID(full.Artists)[3].Locations; !reflect.DeepEqual(got, want) {
+		t.Errorf("artist 3 locations = %v, want cached %v", got, want)
+	}
+}
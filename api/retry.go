@@ -0,0 +1,115 @@
+// Package api - retry.go implémente la politique de nouvelle tentative des requêtes HTTP.
+// L'API hébergée sur Heroku renvoie régulièrement des 503 au démarrage à froid : on réessaie
+// avec un backoff exponentiel et du jitter, en respectant l'en-tête Retry-After quand il est présent.
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy décrit combien de fois et à quel rythme une requête est retentée
+type RetryPolicy struct {
+	MaxAttempts int           // nombre total de tentatives, première incluse
+	BaseDelay   time.Duration // délai avant la deuxième tentative, doublé ensuite
+	MaxDelay    time.Duration // plafond d'un délai, Retry-After compris
+	Jitter      float64       // part aléatoire du délai, entre 0 et 1
+}
+
+// DefaultRetryPolicy est la politique utilisée par défaut par le Client
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Jitter:      0.5,
+}
+
+// NoRetry désactive les nouvelles tentatives
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// Do exécute req avec client en réessayant les erreurs réseau et les statuts transitoires.
+// La réponse finale est renvoyée telle quelle, y compris si son statut reste en erreur.
+func (p RetryPolicy) Do(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	attempts := p.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Do(req.Clone(ctx))
+
+		if attempt >= attempts || !shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+
+		delay := p.backoff(attempt)
+		if resp != nil {
+			if wait, ok := retryAfter(resp); ok && wait > delay {
+				delay = wait
+			}
+			resp.Body.Close()
+		}
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff calcule le délai exponentiel avant la tentative suivant attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		spread := float64(delay) * p.Jitter
+		delay = time.Duration(float64(delay) - spread + rand.Float64()*2*spread)
+	}
+	return delay
+}
+
+// shouldRetry indique si l'échec est transitoire et mérite une nouvelle tentative
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter lit l'en-tête Retry-After, exprimé en secondes ou en date HTTP
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at), true
+	}
+	return 0, false
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// scriptedServer répond successivement les statuts de script, puis le dernier indéfiniment
+func scriptedServer(t *testing.T, script []int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		status := script[min(n, len(script))-1]
+		if status != http.StatusOK {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	fast := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 20 * time.Millisecond}
+
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		script     []int
+		header     http.Header
+		wantStatus int
+		wantCalls  int32
+		minElapsed time.Duration
+		maxElapsed time.Duration
+	}{
+		{
+			name:       "503 puis 200",
+			policy:     fast,
+			script:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "échecs jusqu'à épuisement des tentatives",
+			policy:     fast,
+			script:     []int{http.StatusBadGateway},
+			wantStatus: http.StatusBadGateway,
+			wantCalls:  3,
+		},
+		{
+			name:       "Retry-After en secondes plafonné par MaxDelay",
+			policy:     fast,
+			script:     []int{http.StatusTooManyRequests, http.StatusOK},
+			header:     http.Header{"Retry-After": {"1"}},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+			minElapsed: 20 * time.Millisecond,
+			maxElapsed: 500 * time.Millisecond,
+		},
+		{
+			name:       "Retry-After en date HTTP plafonné par MaxDelay",
+			policy:     fast,
+			script:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			header:     http.Header{"Retry-After": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+			minElapsed: 20 * time.Millisecond,
+			maxElapsed: 500 * time.Millisecond,
+		},
+		{
+			name:       "404 non retenté",
+			policy:     fast,
+			script:     []int{http.StatusNotFound, http.StatusOK},
+			wantStatus: http.StatusNotFound,
+			wantCalls:  1,
+		},
+		{
+			name:       "MaxAttempts nul vaut une tentative",
+			policy:     RetryPolicy{MaxAttempts: 0, BaseDelay: time.Millisecond},
+			script:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+		{
+			name:       "MaxAttempts négatif vaut une tentative",
+			policy:     RetryPolicy{MaxAttempts: -2, BaseDelay: time.Millisecond},
+			script:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := scriptedServer(t, tt.script, tt.header)
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+			resp, err := tt.policy.Do(context.Background(), srv.Client(), req)
+			elapsed := time.Since(start)
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("elapsed = %v, want >= %v", elapsed, tt.minElapsed)
+			}
+			if tt.maxElapsed > 0 && elapsed > tt.maxElapsed {
+				t.Errorf("elapsed = %v, want <= %v", elapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDoCancelDuringBackoff(t *testing.T) {
+	srv, calls := scriptedServer(t, []int{http.StatusServiceUnavailable}, nil)
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	resp, err := policy.Do(ctx, srv.Client(), req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("elapsed = %v, backoff was not interrupted", elapsed)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
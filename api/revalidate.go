@@ -0,0 +1,115 @@
+// Package api - revalidate.go mémorise les réponses de l'API avec leurs validateurs HTTP.
+// Chaque requête suivante envoie If-None-Match / If-Modified-Since : un 304 réutilise le corps déjà
+// téléchargé au lieu de retransférer tout le JSON. Les compteurs de hits/misses servent au diagnostic.
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// CacheStats compte les réponses servies depuis le cache (304) et celles téléchargées
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// cachedResponse est une réponse mémorisée avec ses validateurs
+type cachedResponse struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// responseCache garde les réponses en mémoire et, si dir est défini, sur disque
+type responseCache struct {
+	dir     string
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+func newResponseCache(dir string) *responseCache {
+	return &responseCache{dir: dir, entries: make(map[string]*cachedResponse)}
+}
+
+// get renvoie la réponse mémorisée pour url, en la chargeant depuis le disque si besoin
+func (rc *responseCache) get(url string) *cachedResponse {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if entry, ok := rc.entries[url]; ok {
+		return entry
+	}
+	if rc.dir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(rc.path(url))
+	if err != nil {
+		return nil
+	}
+	var entry cachedResponse
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return nil
+	}
+	rc.entries[url] = &entry
+	return &entry
+}
+
+// store mémorise body si la réponse porte au moins un validateur
+func (rc *responseCache) store(url string, header http.Header, body []byte) {
+	entry := &cachedResponse{
+		URL:          url,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		Body:         body,
+	}
+	if entry.ETag == "" && entry.LastModified == "" {
+		return
+	}
+
+	rc.mu.Lock()
+	rc.entries[url] = entry
+	rc.mu.Unlock()
+
+	if rc.dir == "" {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	// Un échec d'écriture n'a pour effet qu'un téléchargement complet au prochain lancement
+	if err := os.MkdirAll(rc.dir, 0o755); err == nil {
+		_ = os.WriteFile(rc.path(url), data, 0o644)
+	}
+}
+
+// path renvoie le fichier de cache associé à url
+func (rc *responseCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(rc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// setValidators ajoute à req les en-têtes conditionnels de l'entrée en cache
+func (entry *cachedResponse) setValidators(req *http.Request) {
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// stats renvoie les compteurs courants
+func (rc *responseCache) stats() CacheStats {
+	return CacheStats{Hits: rc.hits.Load(), Misses: rc.misses.Load()}
+}
@@ -0,0 +1,44 @@
+// Package main lance un serveur local qui simule l'API Groupie Trackers.
+// Il permet de développer sans connexion internet :
+//
+//	go run ./cmd/mockserver -addr :8080
+//	GROUPIE_API_URL=http://localhost:8080/api go run main.go
+package main
+
+import (
+	"Groupie-Tracker/mockapi"
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "adresse d'écoute")
+	latency := flag.Duration("latency", 0, "latence ajoutée à chaque réponse (ex: 300ms)")
+	errorRate := flag.Float64("error-rate", 0, "part des requêtes qui répondent 503, entre 0 et 1")
+	fail := flag.String("fail", "", "chemins qui répondent toujours 500, séparés par des virgules (\"/api/locations/*\" couvre les sous-chemins)")
+	flag.Parse()
+
+	opts := []mockapi.Option{
+		mockapi.WithLatency(*latency),
+		mockapi.WithErrorRate(*errorRate),
+	}
+	if *fail != "" {
+		opts = append(opts, mockapi.WithFailingPaths(strings.Split(*fail, ",")...))
+	}
+
+	handler, err := mockapi.NewHandler(opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	log.Printf("API Groupie Trackers simulée sur http://localhost%s/api", *addr)
+	log.Fatal(server.ListenAndServe())
+}
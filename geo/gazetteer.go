@@ -0,0 +1,86 @@
+// Package geo - gazetteer.go est le backend de géocodage hors ligne.
+// Il résout les lieux "ville-pays" du jeu de données à partir d'un fichier CSV embarqué,
+// sans aucun appel réseau.
+package geo
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed gazetteer.csv
+var bundledGazetteer string
+
+// Gazetteer associe des lieux normalisés à leur position
+type Gazetteer struct {
+	places map[string]Point
+}
+
+var (
+	defaultGazetteer     *Gazetteer
+	defaultGazetteerErr  error
+	defaultGazetteerOnce sync.Once
+)
+
+// DefaultGazetteer renvoie le gazetteer embarqué, chargé une seule fois
+func DefaultGazetteer() (*Gazetteer, error) {
+	defaultGazetteerOnce.Do(func() {
+		defaultGazetteer, defaultGazetteerErr = LoadGazetteer(strings.NewReader(bundledGazetteer))
+	})
+	return defaultGazetteer, defaultGazetteerErr
+}
+
+// LoadGazetteer lit des lignes "lieu,lat,lon" ; les lignes vides et celles commençant par # sont ignorées
+func LoadGazetteer(r io.Reader) (*Gazetteer, error) {
+	g := &Gazetteer{places: make(map[string]Point)}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, ",")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("gazetteer line %d: expected 3 fields, got %d", line, len(fields))
+		}
+		lat, err := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("gazetteer line %d: latitude: %w", line, err)
+		}
+		lon, err := strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("gazetteer line %d: longitude: %w", line, err)
+		}
+		g.places[NormalizeKey(fields[0])] = Point{Lat: lat, Lon: lon}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Geocode renvoie la position connue de query, ou ErrNotFound
+func (g *Gazetteer) Geocode(ctx context.Context, query string) (Point, error) {
+	if err := ctx.Err(); err != nil {
+		return Point{}, err
+	}
+	if point, ok := g.places[NormalizeKey(query)]; ok {
+		return point, nil
+	}
+	return Point{}, ErrNotFound
+}
+
+// Len renvoie le nombre de lieux connus
+func (g *Gazetteer) Len() int {
+	return len(g.places)
+}
@@ -0,0 +1,63 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDefaultGazetteerKnownPlaces(t *testing.T) {
+	g, err := DefaultGazetteer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  Point
+	}{
+		{"london-uk", Point{Lat: 51.5074, Lon: -0.1278}},
+		{"paris-france", Point{Lat: 48.8566, Lon: 2.3522}},
+		{"new_york-usa", Point{Lat: 40.7128, Lon: -74.0060}},
+		// Les variantes de séparateurs et de casse désignent le même lieu
+		{"North_Carolina-USA", Point{Lat: 35.7596, Lon: -79.0193}},
+		{"los angeles, usa", Point{Lat: 34.0522, Lon: -118.2437}},
+	}
+	for _, tt := range tests {
+		got, err := g.Geocode(context.Background(), tt.query)
+		if err != nil {
+			t.Errorf("Geocode(%q): %v", tt.query, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Geocode(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestGazetteerUnknownPlace(t *testing.T) {
+	g, err := DefaultGazetteer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Geocode(context.Background(), "atlantis-nowhere"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLoadGazetteer(t *testing.T) {
+	g, err := LoadGazetteer(strings.NewReader("# commentaire\n\nlyon-france,45.76,4.84\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Len() != 1 {
+		t.Errorf("Len = %d, want 1", g.Len())
+	}
+
+	for _, bad := range []string{"lyon-france,45.76\n", "lyon-france,north,4.84\n", "lyon-france,45.76,east\n"} {
+		if _, err := LoadGazetteer(strings.NewReader(bad)); err == nil {
+			t.Errorf("LoadGazetteer(%q): want an error", bad)
+		}
+	}
+}
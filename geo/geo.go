@@ -0,0 +1,18 @@
+// Package geo regroupe le géocodage des lieux de concert.
+// Il convertit les chaînes "ville, pays" en coordonnées, en respectant les limites d'usage
+// des services distants (un appel par seconde pour Nominatim) et en mutualisant les requêtes identiques.
+package geo
+
+import "errors"
+
+// Point est une position géographique en degrés décimaux
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// ErrNotFound signale un lieu inconnu du service de géocodage
+var ErrNotFound = errors.New("geo: location not found")
+
+// ErrUnavailable signale un échec transitoire du service (réseau, 429, 5xx) qui mérite une nouvelle tentative
+var ErrUnavailable = errors.New("geo: service temporarily unavailable")
@@ -0,0 +1,68 @@
+// Package geo - geocoder.go définit l'interface commune des backends de géocodage.
+// Les backends (Nominatim en ligne, gazetteer hors ligne) sont interchangeables et combinables
+// avec Chain, ce qui permet d'afficher les cartes sans réseau et de rendre les tests déterministes.
+package geo
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Geocoder convertit un lieu en position ; il renvoie ErrNotFound pour un lieu inconnu
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (Point, error)
+}
+
+// GeocoderFunc adapte une fonction en Geocoder
+type GeocoderFunc func(ctx context.Context, query string) (Point, error)
+
+// Geocode appelle f
+func (f GeocoderFunc) Geocode(ctx context.Context, query string) (Point, error) {
+	return f(ctx, query)
+}
+
+// Nominatim est le backend en ligne ; NewNominatim l'enveloppe dans un Service pour respecter son débit
+type Nominatim struct{}
+
+// Geocode interroge Nominatim
+func (Nominatim) Geocode(ctx context.Context, query string) (Point, error) {
+	return SearchNominatim(ctx, query)
+}
+
+// NewNominatim renvoie le backend Nominatim limité à une requête par seconde, avec déduplication
+func NewNominatim() *Service {
+	return NewService(Nominatim{}, NominatimInterval)
+}
+
+// chain essaie plusieurs backends dans l'ordre
+type chain []Geocoder
+
+// Chain renvoie un Geocoder qui interroge les backends dans l'ordre jusqu'au premier résultat.
+// ErrNotFound n'est renvoyée que si tous les backends l'ont renvoyée ; sinon la dernière erreur l'emporte.
+func Chain(geocoders ...Geocoder) Geocoder {
+	return chain(geocoders)
+}
+
+func (c chain) Geocode(ctx context.Context, query string) (Point, error) {
+	err := ErrNotFound
+	for _, g := range c {
+		point, gErr := g.Geocode(ctx, query)
+		if gErr == nil {
+			return point, nil
+		}
+		if !errors.Is(gErr, ErrNotFound) {
+			err = gErr
+		}
+	}
+	return Point{}, err
+}
+
+// NormalizeKey réduit un lieu à une clé de comparaison :
+// "North_Carolina-USA", "north, carolina, usa" et "north carolina usa" donnent la même clé
+func NormalizeKey(query string) string {
+	fields := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return r == '-' || r == '_' || r == ',' || r == ' '
+	})
+	return strings.Join(fields, " ")
+}
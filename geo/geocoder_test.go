@@ -0,0 +1,74 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fixed renvoie un Geocoder qui répond toujours point et err
+func fixed(point Point, err error) Geocoder {
+	return GeocoderFunc(func(context.Context, string) (Point, error) {
+		return point, err
+	})
+}
+
+func TestChain(t *testing.T) {
+	paris := Point{Lat: 48.8566, Lon: 2.3522}
+	errNetwork := errors.New("network down")
+	errTimeout := errors.New("timeout")
+
+	tests := []struct {
+		name      string
+		geocoders []Geocoder
+		want      Point
+		wantErr   error
+	}{
+		{
+			name:    "aucun backend",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:      "premier résultat",
+			geocoders: []Geocoder{fixed(paris, nil), fixed(Point{}, errNetwork)},
+			want:      paris,
+		},
+		{
+			name:      "repli après un lieu introuvable",
+			geocoders: []Geocoder{fixed(Point{}, ErrNotFound), fixed(paris, nil)},
+			want:      paris,
+		},
+		{
+			name:      "repli après une erreur",
+			geocoders: []Geocoder{fixed(Point{}, errNetwork), fixed(paris, nil)},
+			want:      paris,
+		},
+		{
+			name:      "introuvable partout",
+			geocoders: []Geocoder{fixed(Point{}, ErrNotFound), fixed(Point{}, ErrNotFound)},
+			wantErr:   ErrNotFound,
+		},
+		{
+			name:      "une erreur l'emporte sur ErrNotFound",
+			geocoders: []Geocoder{fixed(Point{}, errNetwork), fixed(Point{}, ErrNotFound)},
+			wantErr:   errNetwork,
+		},
+		{
+			name:      "la dernière erreur l'emporte",
+			geocoders: []Geocoder{fixed(Point{}, errNetwork), fixed(Point{}, ErrNotFound), fixed(Point{}, errTimeout)},
+			wantErr:   errTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Chain(tt.geocoders...).Geocode(context.Background(), "paris-france")
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("point = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Package geo - nominatim.go interroge l'API de recherche de Nominatim (OpenStreetMap).
+package geo
+
+import (
+	"Groupie-Tracker/api"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// nominatimEndpoint est l'URL de recherche publique de Nominatim
+const nominatimEndpoint = "https://nominatim.openstreetmap.org/search"
+
+// nominatimClient n'effectue qu'une tentative par appel : les nouvelles tentatives sont faites
+// par le Service, chacune dans un nouveau créneau du limiteur de débit
+var nominatimClient = api.NewClient(api.WithRetryPolicy(api.NoRetry))
+
+// SearchNominatim renvoie la position de query, ou ErrNotFound si Nominatim ne la connaît pas.
+// Chaque appel est une seule requête réseau : passer par un Service pour respecter la limite d'usage.
+// Les échecs transitoires sont signalés par ErrUnavailable.
+func SearchNominatim(ctx context.Context, query string) (Point, error) {
+	endpoint := fmt.Sprintf("%s?q=%s&format=json&limit=1", nominatimEndpoint, url.QueryEscape(query))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Point{}, err
+	}
+
+	resp, err := nominatimClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return Point{}, ctx.Err()
+		}
+		return Point{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return Point{}, fmt.Errorf("%w: nominatim: %s", ErrUnavailable, resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Point{}, fmt.Errorf("nominatim: %s", resp.Status)
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return Point{}, err
+	}
+	if len(results) == 0 {
+		return Point{}, ErrNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return Point{}, err
+	}
+	lon, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{Lat: lat, Lon: lon}, nil
+}
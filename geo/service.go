@@ -0,0 +1,127 @@
+// Package geo - service.go sérialise les appels de géocodage derrière un limiteur de débit global.
+// Les demandes attendent leur tour dans l'ordre d'arrivée, et les demandes simultanées pour un même
+// lieu partagent une seule requête (single-flight) au lieu d'en émettre une chacune.
+package geo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// NominatimInterval est l'intervalle minimal entre deux requêtes imposé par Nominatim
+const NominatimInterval = time.Second
+
+// maxAttempts borne les tentatives d'un même lieu quand le backend renvoie ErrUnavailable
+const maxAttempts = 3
+
+// Service applique limitation de débit et déduplication à un autre Geocoder
+type Service struct {
+	inner    Geocoder
+	interval time.Duration
+
+	mu       sync.Mutex
+	next     time.Time        // premier créneau libre pour une requête
+	inflight map[string]*call // requêtes en cours, par lieu normalisé
+}
+
+// call est une requête partagée entre tous les demandeurs d'un même lieu
+type call struct {
+	done  chan struct{}
+	point Point
+	err   error
+}
+
+// NewService crée un service qui espace les appels à inner d'au moins interval
+func NewService(inner Geocoder, interval time.Duration) *Service {
+	return &Service{
+		inner:    inner,
+		interval: interval,
+		inflight: make(map[string]*call),
+	}
+}
+
+// Geocode résout query en respectant le débit maximal ; les appels concurrents pour le même lieu
+// sont fusionnés. Si le demandeur qui porte la requête partagée abandonne, les autres la relancent.
+func (s *Service) Geocode(ctx context.Context, query string) (Point, error) {
+	key := NormalizeKey(query)
+
+	for {
+		s.mu.Lock()
+		c, shared := s.inflight[key]
+		if !shared {
+			c = &call{done: make(chan struct{})}
+			s.inflight[key] = c
+		}
+		s.mu.Unlock()
+
+		if !shared {
+			c.point, c.err = s.run(ctx, query)
+			s.mu.Lock()
+			delete(s.inflight, key)
+			s.mu.Unlock()
+			close(c.done)
+			return c.point, c.err
+		}
+
+		select {
+		case <-ctx.Done():
+			return Point{}, ctx.Err()
+		case <-c.done:
+		}
+		if isCancellation(c.err) && ctx.Err() == nil {
+			continue
+		}
+		return c.point, c.err
+	}
+}
+
+// run attend le prochain créneau disponible puis appelle le backend ; chaque nouvelle tentative
+// après ErrUnavailable réserve son propre créneau, si bien que le débit maximal est toujours respecté
+func (s *Service) run(ctx context.Context, query string) (Point, error) {
+	var (
+		point Point
+		err   error
+	)
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := s.wait(ctx); err != nil {
+			return Point{}, err
+		}
+		point, err = s.inner.Geocode(ctx, query)
+		if !errors.Is(err, ErrUnavailable) {
+			break
+		}
+	}
+	return point, err
+}
+
+// wait réserve le prochain créneau et dort jusqu'à lui, ou jusqu'à l'annulation de ctx
+func (s *Service) wait(ctx context.Context) error {
+	s.mu.Lock()
+	now := time.Now()
+	slot := s.next
+	if slot.Before(now) {
+		slot = now
+	}
+	s.next = slot.Add(s.interval)
+	s.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isCancellation indique une erreur due à l'abandon du demandeur plutôt qu'au service
+func isCancellation(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
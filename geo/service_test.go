@@ -0,0 +1,125 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingGeocoder est un Geocoder factice qui compte ses appels et en note les instants
+type countingGeocoder struct {
+	calls   atomic.Int32
+	delay   time.Duration
+	mu      sync.Mutex
+	callsAt []time.Time
+}
+
+func (l *countingGeocoder) Geocode(ctx context.Context, query string) (Point, error) {
+	l.mu.Lock()
+	l.calls.Add(1)
+	l.callsAt = append(l.callsAt, time.Now())
+	l.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return Point{}, ctx.Err()
+	case <-time.After(l.delay):
+		return Point{Lat: 48.85, Lon: 2.35}, nil
+	}
+}
+
+func TestGeocodeCoalescesConcurrentCalls(t *testing.T) {
+	fake := &countingGeocoder{delay: 50 * time.Millisecond}
+	s := NewService(fake, 0)
+
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p, err := s.Geocode(context.Background(), "Paris, France")
+			if err == nil && p.Lat != 48.85 {
+				err = errors.New("unexpected point")
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if got := fake.calls.Load(); got != 1 {
+		t.Errorf("Geocode called %d times, want 1", got)
+	}
+}
+
+func TestGeocodeWaiterRetriesWhenOwnerCancels(t *testing.T) {
+	fake := &countingGeocoder{delay: 50 * time.Millisecond}
+	s := NewService(fake, 0)
+
+	ownerCtx, cancelOwner := context.WithCancel(context.Background())
+	ownerErr := make(chan error, 1)
+	go func() {
+		_, err := s.Geocode(ownerCtx, "Paris, France")
+		ownerErr <- err
+	}()
+
+	// Le second demandeur rejoint la requête partagée avant que son porteur n'abandonne
+	time.Sleep(10 * time.Millisecond)
+	waiterErr := make(chan error, 1)
+	go func() {
+		_, err := s.Geocode(context.Background(), "paris, france")
+		waiterErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancelOwner()
+
+	if err := <-ownerErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("owner err = %v, want context.Canceled", err)
+	}
+	if err := <-waiterErr; err != nil {
+		t.Errorf("waiter err = %v, want the request re-issued", err)
+	}
+	if got := fake.calls.Load(); got != 2 {
+		t.Errorf("Geocode called %d times, want 2", got)
+	}
+}
+
+func TestGeocodeSpacesCallsByInterval(t *testing.T) {
+	const interval = 30 * time.Millisecond
+	fake := &countingGeocoder{}
+	s := NewService(fake, interval)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, q := range []string{"Paris", "London", "Berlin", "Madrid"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Geocode(context.Background(), q); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(fake.callsAt) != 4 {
+		t.Fatalf("Geocode called %d times, want 4", len(fake.callsAt))
+	}
+	// Le k-ième appel ne peut partir avant son créneau, k intervalles après le premier ;
+	// comparer au départ du test évite de dépendre de la latence d'ordonnancement d'un appel isolé
+	for k, at := range fake.callsAt {
+		if earliest := time.Duration(k) * interval; at.Sub(start) < earliest {
+			t.Errorf("call %d started %v after start, want >= %v", k, at.Sub(start), earliest)
+		}
+	}
+}
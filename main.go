@@ -4,9 +4,59 @@
 package main
 
 import (
+	"Groupie-Tracker/api"
+	"Groupie-Tracker/geo"
 	"Groupie-Tracker/ui"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
 )
 
 func main() {
+	var opts []api.Option
+
+	// Les réponses de l'API sont conservées entre deux lancements pour être revalidées
+	if dir, err := api.AppCacheDir(); err == nil {
+		opts = append(opts, api.WithResponseCacheDir(filepath.Join(dir, "http")))
+	}
+
+	// GROUPIE_API_URL permet de pointer l'application vers un miroir local de l'API
+	if baseURL := os.Getenv("GROUPIE_API_URL"); baseURL != "" {
+		opts = append(opts, api.WithBaseURL(baseURL))
+	}
+
+	api.DefaultClient = api.NewClient(opts...)
+
+	// GROUPIE_TILE_URL pointe les cartes vers un autre serveur de tuiles ({z}, {x}, {y}),
+	// GROUPIE_TILE_CACHE_MB borne la taille du cache disque des tuiles (un entier strictement positif)
+	tileURL := os.Getenv("GROUPIE_TILE_URL")
+	tileCacheBytes := int64(ui.DefaultTileCacheSize)
+	if v := os.Getenv("GROUPIE_TILE_CACHE_MB"); v != "" {
+		if mb, err := strconv.Atoi(v); err == nil && mb > 0 {
+			tileCacheBytes = int64(mb) << 20
+		} else {
+			log.Printf("GROUPIE_TILE_CACHE_MB=%q ignoré : un nombre de Mo strictement positif est attendu", v)
+		}
+	}
+	if tileURL != "" || tileCacheBytes != ui.DefaultTileCacheSize {
+		if tileURL == "" {
+			tileURL = ui.DefaultTileURL
+		}
+		ui.SetTileServer(tileURL, tileCacheBytes)
+	}
+
+	// GROUPIE_GEOCODER=offline n'utilise que le gazetteer embarqué, =nominatim que le service en ligne
+	switch os.Getenv("GROUPIE_GEOCODER") {
+	case "offline":
+		gazetteer, err := geo.DefaultGazetteer()
+		if err != nil {
+			log.Fatal(err)
+		}
+		ui.SetGeocoder(gazetteer, false)
+	case "nominatim":
+		ui.SetGeocoder(geo.NewNominatim(), true)
+	}
+
 	ui.StartApp()
 }
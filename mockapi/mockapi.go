@@ -0,0 +1,243 @@
+// Package mockapi simule localement l'API Groupie Trackers à partir de fixtures JSON embarquées.
+// Il sert /api/artists, /api/locations, /api/dates et /api/relation (index et détail par id),
+// avec de la latence et des erreurs injectables pour développer et tester sans connexion internet.
+package mockapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// upstreamBaseURL est réécrite vers l'adresse du serveur local dans les réponses
+const upstreamBaseURL = "https://groupietrackers.herokuapp.com/api"
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// collection contient les entrées d'un endpoint, indexées par id
+type collection struct {
+	list  []json.RawMessage
+	byID  map[int]json.RawMessage
+	index bool // vrai si la liste est servie sous la forme {"index": [...]}
+}
+
+// Option modifie le comportement du serveur simulé
+type Option func(*handler)
+
+// WithLatency retarde chaque réponse de d
+func WithLatency(d time.Duration) Option {
+	return func(h *handler) {
+		h.latency = d
+	}
+}
+
+// WithErrorRate fait répondre 503 à une part rate (entre 0 et 1) des requêtes, tirée au hasard
+func WithErrorRate(rate float64) Option {
+	return func(h *handler) {
+		h.errorRate = rate
+	}
+}
+
+// WithFailingPaths fait toujours répondre 500 aux chemins donnés. Un chemin est comparé exactement
+// ("/api/locations" ne touche que l'index) ; un motif terminé par "/*" couvre tous les segments
+// en dessous ("/api/locations/*" touche "/api/locations/1" mais pas l'index).
+func WithFailingPaths(paths ...string) Option {
+	return func(h *handler) {
+		h.failPaths = append(h.failPaths, paths...)
+	}
+}
+
+// matchFailPath indique si path correspond au motif pattern de WithFailingPaths
+func matchFailPath(pattern, path string) bool {
+	path = "/" + strings.Trim(path, "/")
+	if base, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(path, "/"+strings.Trim(base, "/")+"/")
+	}
+	return path == "/"+strings.Trim(pattern, "/")
+}
+
+type handler struct {
+	collections map[string]*collection
+	latency     time.Duration
+	errorRate   float64
+	failPaths   []string
+}
+
+// NewHandler crée le handler HTTP du serveur simulé
+func NewHandler(opts ...Option) (http.Handler, error) {
+	h := &handler{collections: make(map[string]*collection)}
+	for name, index := range map[string]bool{"artists": false, "locations": true, "dates": true, "relation": true} {
+		c, err := loadCollection(name, index)
+		if err != nil {
+			return nil, fmt.Errorf("load fixture %s: %w", name, err)
+		}
+		h.collections[name] = c
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h, nil
+}
+
+// NewServer démarre un httptest.Server ; l'API est servie sous server.URL + "/api"
+func NewServer(opts ...Option) (*httptest.Server, error) {
+	h, err := NewHandler(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return httptest.NewServer(h), nil
+}
+
+// loadCollection lit une fixture et indexe ses entrées par id
+func loadCollection(name string, index bool) (*collection, error) {
+	data, err := fixtures.ReadFile("fixtures/" + name + ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &collection{byID: make(map[int]json.RawMessage), index: index}
+	if index {
+		var payload struct {
+			Index []json.RawMessage `json:"index"`
+		}
+		err = json.Unmarshal(data, &payload)
+		c.list = payload.Index
+	} else {
+		err = json.Unmarshal(data, &c.list)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, raw := range c.list {
+		var entry struct {
+			Id int `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return nil, err
+		}
+		c.byID[entry.Id] = raw
+	}
+	return c, nil
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.latency > 0 {
+		select {
+		case <-time.After(h.latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	for _, pattern := range h.failPaths {
+		if matchFailPath(pattern, r.URL.Path) {
+			http.Error(w, "injected failure", http.StatusInternalServerError)
+			return
+		}
+	}
+	if h.errorRate > 0 && rand.Float64() < h.errorRate {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "injected cold start", http.StatusServiceUnavailable)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/images/") {
+		writePlaceholderImage(w, r.URL.Path)
+		return
+	}
+
+	body, ok := h.lookup(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api"), "/"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	body = bytes.ReplaceAll(body, []byte(upstreamBaseURL), []byte("http://"+r.Host+"/api"))
+	writeJSON(w, r, body)
+}
+
+// lookup résout "artists", "artists/3", "relation/3"... en corps JSON
+func (h *handler) lookup(path string) ([]byte, bool) {
+	if path == "" {
+		body, err := json.Marshal(map[string]string{
+			"artists":   upstreamBaseURL + "/artists",
+			"locations": upstreamBaseURL + "/locations",
+			"dates":     upstreamBaseURL + "/dates",
+			"relation":  upstreamBaseURL + "/relation",
+		})
+		return body, err == nil
+	}
+
+	name, idPart, hasID := strings.Cut(path, "/")
+	c, ok := h.collections[name]
+	if !ok {
+		return nil, false
+	}
+	if !hasID {
+		if c.index {
+			body, err := json.Marshal(map[string][]json.RawMessage{"index": c.list})
+			return body, err == nil
+		}
+		body, err := json.Marshal(c.list)
+		return body, err == nil
+	}
+
+	id, err := strconv.Atoi(idPart)
+	if err != nil {
+		return nil, false
+	}
+	raw, ok := c.byID[id]
+	return raw, ok
+}
+
+// writeJSON écrit body avec un ETag et répond 304 si le client possède déjà cette version
+func writeJSON(w http.ResponseWriter, r *http.Request, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(body)
+}
+
+// writePlaceholderImage sert une image unie dont la couleur dépend du chemin demandé
+func writePlaceholderImage(w http.ResponseWriter, path string) {
+	hash := fnv.New32a()
+	hash.Write([]byte(path))
+	sum := hash.Sum32()
+	fill := color.NRGBA{R: uint8(sum), G: uint8(sum >> 8), B: uint8(sum >> 16), A: 255}
+
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = fill.R, fill.G, fill.B, fill.A
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	png.Encode(w, img)
+}
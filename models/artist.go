@@ -5,14 +5,30 @@
 package models
 
 type Artist struct {
-	Id              int      `json:"id"`
-	Name            string   `json:"name"`
-	Image           string   `json:"image"`
-	Members         []string `json:"members"`
-	CreationDate    int      `json:"creationDate"`
-	FirstAlbum      string   `json:"firstAlbum"`
-	LocationsURL    string   `json:"locations"`
-	ConcertDatesURL string   `json:"concertDates"`
-	RelationsURL    string   `json:"relations"`
-	Locations       []string `json:"-"`
+	Id              int                 `json:"id"`
+	Name            string              `json:"name"`
+	Image           string              `json:"image"`
+	Members         []string            `json:"members"`
+	CreationDate    int                 `json:"creationDate"`
+	FirstAlbum      string              `json:"firstAlbum"`
+	LocationsURL    string              `json:"locations"`
+	ConcertDatesURL string              `json:"concertDates"`
+	RelationsURL    string              `json:"relations"`
+	Locations       []string            `json:"-"`
+	ConcertDates    []string            `json:"-"`
+	Relations       map[string][]string `json:"-"`
+}
+
+// DatesForLocation renvoie les dates de concert connues pour un lieu
+func (a Artist) DatesForLocation(location string) []string {
+	return a.Relations[location]
+}
+
+// ParsedLocations renvoie les lieux de concert sous forme structurée
+func (a Artist) ParsedLocations() []Location {
+	locations := make([]Location, len(a.Locations))
+	for i, raw := range a.Locations {
+		locations[i] = ParseLocation(raw)
+	}
+	return locations
 }
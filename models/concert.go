@@ -0,0 +1,16 @@
+// Package models - concert.go définit les structures liées aux concerts.
+// Dates et Relation reprennent les réponses des endpoints /dates et /relation de l'API Groupie Trackers.
+// La relation associe chaque lieu à ses dates de concert, ce qui permet d'afficher quand un groupe a joué où.
+package models
+
+// Dates contient les dates de concert d'un artiste
+type Dates struct {
+	Id    int      `json:"id"`
+	Dates []string `json:"dates"`
+}
+
+// Relation associe chaque lieu de concert ("ville-pays") à ses dates
+type Relation struct {
+	Id             int                 `json:"id"`
+	DatesLocations map[string][]string `json:"datesLocations"`
+}
@@ -0,0 +1,162 @@
+// Package models - location.go définit le type Location, forme structurée des lieux de concert.
+// L'API fournit des chaînes "ville-pays" en minuscules avec underscores ("north_carolina-usa") ;
+// ParseLocation en extrait ville, région et pays, avec une casse lisible et le code pays ISO.
+package models
+
+import "strings"
+
+// Location est un lieu de concert décomposé
+type Location struct {
+	Raw         string // chaîne d'origine de l'API, ex: "north_carolina-usa"
+	City        string // ex: "Los Angeles" ; vide si le lieu est une région entière
+	Region      string // état ou province, ex: "North Carolina"
+	Country     string // ex: "USA"
+	CountryCode string // code ISO 3166-1 alpha-2, ex: "US" ; vide si inconnu
+}
+
+// countryInfo donne le libellé et le code ISO des pays du jeu de données
+var countryInfo = map[string]struct{ Name, Code string }{
+	"argentina":            {"Argentina", "AR"},
+	"australia":            {"Australia", "AU"},
+	"austria":              {"Austria", "AT"},
+	"belarus":              {"Belarus", "BY"},
+	"belgium":              {"Belgium", "BE"},
+	"brazil":               {"Brazil", "BR"},
+	"bulgaria":             {"Bulgaria", "BG"},
+	"canada":               {"Canada", "CA"},
+	"chile":                {"Chile", "CL"},
+	"china":                {"China", "CN"},
+	"colombia":             {"Colombia", "CO"},
+	"costa_rica":           {"Costa Rica", "CR"},
+	"czechia":              {"Czechia", "CZ"},
+	"denmark":              {"Denmark", "DK"},
+	"estonia":              {"Estonia", "EE"},
+	"finland":              {"Finland", "FI"},
+	"france":               {"France", "FR"},
+	"french_polynesia":     {"French Polynesia", "PF"},
+	"germany":              {"Germany", "DE"},
+	"greece":               {"Greece", "GR"},
+	"hungary":              {"Hungary", "HU"},
+	"iceland":              {"Iceland", "IS"},
+	"india":                {"India", "IN"},
+	"indonesia":            {"Indonesia", "ID"},
+	"ireland":              {"Ireland", "IE"},
+	"israel":               {"Israel", "IL"},
+	"italy":                {"Italy", "IT"},
+	"japan":                {"Japan", "JP"},
+	"latvia":               {"Latvia", "LV"},
+	"lithuania":            {"Lithuania", "LT"},
+	"malaysia":             {"Malaysia", "MY"},
+	"mexico":               {"Mexico", "MX"},
+	"netherlands":          {"Netherlands", "NL"},
+	"netherlands_antilles": {"Netherlands Antilles", "AN"},
+	"new_caledonia":        {"New Caledonia", "NC"},
+	"new_zealand":          {"New Zealand", "NZ"},
+	"norway":               {"Norway", "NO"},
+	"peru":                 {"Peru", "PE"},
+	"philippines":          {"Philippines", "PH"},
+	"poland":               {"Poland", "PL"},
+	"portugal":             {"Portugal", "PT"},
+	"qatar":                {"Qatar", "QA"},
+	"romania":              {"Romania", "RO"},
+	"russia":               {"Russia", "RU"},
+	"saudi_arabia":         {"Saudi Arabia", "SA"},
+	"singapore":            {"Singapore", "SG"},
+	"slovakia":             {"Slovakia", "SK"},
+	"south_africa":         {"South Africa", "ZA"},
+	"south_korea":          {"South Korea", "KR"},
+	"spain":                {"Spain", "ES"},
+	"sweden":               {"Sweden", "SE"},
+	"switzerland":          {"Switzerland", "CH"},
+	"taiwan":               {"Taiwan", "TW"},
+	"thailand":             {"Thailand", "TH"},
+	"turkey":               {"Turkey", "TR"},
+	"uk":                   {"UK", "GB"},
+	"ukraine":              {"Ukraine", "UA"},
+	"united_arab_emirates": {"United Arab Emirates", "AE"},
+	"uruguay":              {"Uruguay", "UY"},
+	"usa":                  {"USA", "US"},
+}
+
+// regions liste les états et provinces que l'API utilise à la place d'une ville
+var regions = map[string]bool{
+	"alabama": true, "arizona": true, "california": true, "colorado": true, "florida": true,
+	"georgia": true, "illinois": true, "massachusetts": true, "michigan": true, "minnesota": true,
+	"missouri": true, "nevada": true, "new_jersey": true, "north_carolina": true, "ohio": true,
+	"oregon": true, "pennsylvania": true, "south_carolina": true, "texas": true, "utah": true,
+	"washington": true, "new_south_wales": true, "queensland": true, "victoria": true, "quebec": true,
+}
+
+// lowercaseWords restent en minuscules à l'intérieur d'un nom ("Playa del Carmen")
+var lowercaseWords = map[string]bool{
+	"de": true, "del": true, "la": true, "le": true, "on": true, "of": true, "da": true, "do": true,
+}
+
+// ParseLocation décompose une chaîne "ville-pays" de l'API ; une chaîne sans tiret
+// est un pays si elle en nomme un connu ("usa"), sinon une ville de pays inconnu
+func ParseLocation(raw string) Location {
+	loc := Location{Raw: raw}
+
+	place, country := strings.TrimSpace(strings.ToLower(raw)), ""
+	if i := strings.LastIndex(place, "-"); i >= 0 {
+		place, country = place[:i], place[i+1:]
+	} else if _, ok := countryInfo[place]; ok {
+		place, country = "", place
+	}
+
+	if info, ok := countryInfo[country]; ok {
+		loc.Country, loc.CountryCode = info.Name, info.Code
+	} else {
+		loc.Country = titleCase(country)
+	}
+
+	if regions[place] {
+		loc.Region = titleCase(place)
+	} else {
+		loc.City = titleCase(place)
+	}
+	return loc
+}
+
+// Place renvoie la ville, ou la région quand le lieu n'a pas de ville
+func (l Location) Place() string {
+	if l.City != "" {
+		return l.City
+	}
+	return l.Region
+}
+
+// String renvoie la forme lisible du lieu, ex: "North Carolina, USA"
+func (l Location) String() string {
+	parts := make([]string, 0, 3)
+	for _, p := range []string{l.City, l.Region, l.Country} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Matches indique si la requête q apparaît dans la forme lisible ou brute du lieu, sans tenir compte de la casse
+func (l Location) Matches(q string) bool {
+	q = strings.TrimSpace(strings.ToLower(q))
+	if q == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(l.String()), q) ||
+		strings.Contains(strings.ToLower(l.Raw), q) ||
+		strings.EqualFold(l.CountryCode, q)
+}
+
+// titleCase met en majuscule chaque mot d'un segment "mot_mot"
+func titleCase(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == ' ' })
+	for i, w := range words {
+		if i > 0 && lowercaseWords[w] {
+			continue
+		}
+		r := []rune(w)
+		words[i] = strings.ToUpper(string(r[0])) + string(r[1:])
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,94 @@
+package models
+
+import "testing"
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Location
+	}{
+		{
+			name: "ville",
+			raw:  "los_angeles-usa",
+			want: Location{City: "Los Angeles", Country: "USA", CountryCode: "US"},
+		},
+		{
+			name: "région sans ville",
+			raw:  "north_carolina-usa",
+			want: Location{Region: "North Carolina", Country: "USA", CountryCode: "US"},
+		},
+		{
+			name: "particules en minuscules",
+			raw:  "playa_del_carmen-mexico",
+			want: Location{City: "Playa del Carmen", Country: "Mexico", CountryCode: "MX"},
+		},
+		{
+			name: "code pays différent du nom",
+			raw:  "london-uk",
+			want: Location{City: "London", Country: "UK", CountryCode: "GB"},
+		},
+		{
+			name: "pays inconnu",
+			raw:  "springfield-freedonia",
+			want: Location{City: "Springfield", Country: "Freedonia"},
+		},
+		{
+			name: "casse et espaces ignorés",
+			raw:  "  Paris-FRANCE ",
+			want: Location{City: "Paris", Country: "France", CountryCode: "FR"},
+		},
+		{
+			name: "pays seul",
+			raw:  "usa",
+			want: Location{Country: "USA", CountryCode: "US"},
+		},
+		{
+			name: "sans tiret ni pays connu",
+			raw:  "atlantis",
+			want: Location{City: "Atlantis"},
+		},
+		{
+			name: "pays manquant",
+			raw:  "paris-",
+			want: Location{City: "Paris"},
+		},
+		{
+			name: "ville manquante",
+			raw:  "-france",
+			want: Location{Country: "France", CountryCode: "FR"},
+		},
+		{
+			name: "chaîne vide",
+			raw:  "",
+			want: Location{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.Raw = tt.raw
+			if got := ParseLocation(tt.raw); got != tt.want {
+				t.Errorf("ParseLocation(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocationString(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"los_angeles-usa", "Los Angeles, USA"},
+		{"north_carolina-usa", "North Carolina, USA"},
+		{"usa", "USA"},
+		{"paris-", "Paris"},
+	}
+
+	for _, tt := range tests {
+		if got := ParseLocation(tt.raw).String(); got != tt.want {
+			t.Errorf("ParseLocation(%q).String() = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
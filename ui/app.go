@@ -6,12 +6,13 @@ package ui
 import (
 	"Groupie-Tracker/api"
 	"Groupie-Tracker/models"
+	"context"
 	"fmt"
+	"image"
 	"image/color"
-	"io"
-	"net/http"
-	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -30,6 +31,8 @@ func StartApp() {
 
 	w.SetContent(CreateMainLayout(a, w))
 	w.ShowAndRun()
+
+	cleanupAssets()
 }
 
 type AppState struct {
@@ -38,11 +41,22 @@ type AppState struct {
 	allArtists     []models.Artist
 	mainContent    *fyne.Container
 	selectedArtist *models.Artist
+	ctx            context.Context
+	viewCancel     context.CancelFunc
+	failed         map[int]error
+	statusLabel    *widget.Label
+	showingGrid    bool
 }
 
 // CreateMainLayout crée le layout Spotify-like avec sidebar et contenu principal
 func CreateMainLayout(app fyne.App, window fyne.Window) fyne.CanvasObject {
-	artists, err := api.GetArtists()
+	// Fermer la fenêtre annule toutes les requêtes encore en cours
+	ctx, cancel := context.WithCancel(context.Background())
+	window.SetOnClosed(cancel)
+
+	// Un cache indisponible n'est pas bloquant : on se contente de l'API
+	cache, _ := api.DefaultDatasetCache()
+	data, err := loadDataset(ctx, cache)
 	if err != nil {
 		return widget.NewLabel("Erreur: " + err.Error())
 	}
@@ -50,15 +64,25 @@ func CreateMainLayout(app fyne.App, window fyne.Window) fyne.CanvasObject {
 	state := &AppState{
 		app:        app,
 		window:     window,
-		allArtists: artists,
+		allArtists: data.artists,
+		ctx:        ctx,
+		failed:     data.failed,
 	}
 
+	state.statusLabel = widget.NewLabel("")
+	state.statusLabel.Wrapping = fyne.TextWrapWord
+	state.statusLabel.Hide()
+
 	sidebar := createSidebar(state)
 
 	state.mainContent = container.NewVBox()
 	mainScroll := container.NewVScroll(state.mainContent)
 
-	displayArtistGrid(state, artists)
+	displayArtistGrid(state, data.artists)
+
+	if data.stale {
+		state.refreshDataset(cache, data.cachedAt)
+	}
 
 	mainLayout := container.NewHBox(
 		sidebar,
@@ -93,16 +117,23 @@ func createSidebar(state *AppState) fyne.CanvasObject {
 	})
 	filterBtn.Importance = widget.MediumImportance
 
+	concertMapBtn := widget.NewButton("🌍 Carte des concerts", func() {
+		displayConcertMapView(state)
+	})
+	concertMapBtn.Importance = widget.MediumImportance
+
 	buttonsBox := container.NewVBox(
 		allArtistsBtn,
 		searchBtn,
 		filterBtn,
+		concertMapBtn,
 	)
 
 	sidebarContent := container.NewVBox(
 		title,
 		sep1,
 		buttonsBox,
+		state.statusLabel,
 	)
 
 	sidebarRect := container.NewStack(bg)
@@ -112,11 +143,45 @@ func createSidebar(state *AppState) fyne.CanvasObject {
 	return container.NewStack(sizeRect, sidebarRect, container.NewPadded(sidebarContent))
 }
 
+// newViewContext annule le travail de la vue précédente et renvoie le contexte de la nouvelle vue
+func (s *AppState) newViewContext() context.Context {
+	s.showingGrid = false
+	if s.viewCancel != nil {
+		s.viewCancel()
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.viewCancel = cancel
+	return ctx
+}
+
+// refreshDataset recharge les artistes depuis l'API en arrière-plan, en affichant l'état du cache
+func (s *AppState) refreshDataset(cache *api.DatasetCache, cachedAt time.Time) {
+	s.statusLabel.SetText(refreshingText(cachedAt))
+	s.statusLabel.Show()
+
+	runAsync(s.ctx, func(ctx context.Context) (*dataset, error) {
+		return fetchDataset(ctx, cache)
+	}, func(data *dataset, err error) {
+		if err != nil {
+			s.statusLabel.SetText(offlineText(cachedAt))
+			return
+		}
+		s.statusLabel.Hide()
+		s.allArtists = data.artists
+		s.failed = data.failed
+		if s.showingGrid {
+			displayArtistGrid(s, s.allArtists)
+		}
+	})
+}
+
 // displayArtistGrid affiche la grille principale des artistes
 func displayArtistGrid(state *AppState, artists []models.Artist) {
+	ctx := state.newViewContext()
+	state.showingGrid = true
 	state.mainContent.Objects = nil
 
-	header := createMainHeader()
+	header := createMainHeader(ctx)
 	state.mainContent.Add(header)
 	state.mainContent.Add(widget.NewSeparator())
 
@@ -125,37 +190,32 @@ func displayArtistGrid(state *AppState, artists []models.Artist) {
 	gridTitle.Alignment = fyne.TextAlignCenter
 	state.mainContent.Add(gridTitle)
 
-	grid := createArtistGrid(state, artists)
+	grid := createArtistGrid(ctx, state, artists)
 	state.mainContent.Add(grid)
 	state.mainContent.Refresh()
 }
 
 // createMainHeader construit le bandeau visuel avec image vinyle
-func createMainHeader() fyne.CanvasObject {
+func createMainHeader(ctx context.Context) fyne.CanvasObject {
 	vinylURL := "https://images.unsplash.com/photo-1603048588665-791ca8aea617?w=1200&h=300&fit=crop"
 
 	headerBg := canvas.NewRectangle(color.NRGBA{R: 20, G: 20, B: 20, A: 255})
 	headerBg.SetMinSize(fyne.NewSize(1000, 250))
 
 	vinylContainer := container.NewStack(headerBg)
-	go func() {
-		resp, err := http.Get(vinylURL)
-		if err == nil && resp.StatusCode == http.StatusOK {
-			defer resp.Body.Close()
-			tmpFile, err := os.CreateTemp("", "vinyl-bg-*.jpg")
-			if err == nil {
-				defer tmpFile.Close()
-				io.Copy(tmpFile, resp.Body)
-
-				vinylImg := canvas.NewImageFromFile(tmpFile.Name())
-				vinylImg.FillMode = canvas.ImageFillStretch
-				vinylImg.SetMinSize(fyne.NewSize(1000, 250))
-
-				vinylContainer.Objects = []fyne.CanvasObject{vinylImg}
-				vinylContainer.Refresh()
-			}
+	runAsync(ctx, func(ctx context.Context) (image.Image, error) {
+		return sharedImages.get(ctx, vinylURL)
+	}, func(img image.Image, err error) {
+		if err != nil {
+			return
 		}
-	}()
+		vinylImg := canvas.NewImageFromImage(img)
+		vinylImg.FillMode = canvas.ImageFillStretch
+		vinylImg.SetMinSize(fyne.NewSize(1000, 250))
+
+		vinylContainer.Objects = []fyne.CanvasObject{vinylImg}
+		vinylContainer.Refresh()
+	})
 
 	titleText := canvas.NewText("Groupie Tracker", color.NRGBA{R: 255, G: 255, B: 255, A: 255})
 	titleText.TextSize = 60
@@ -181,14 +241,14 @@ func createMainHeader() fyne.CanvasObject {
 }
 
 // createArtistGrid organise les cartes artistes en grille 4 colonnes
-func createArtistGrid(state *AppState, artists []models.Artist) fyne.CanvasObject {
+func createArtistGrid(ctx context.Context, state *AppState, artists []models.Artist) fyne.CanvasObject {
 	grid := container.NewVBox()
 
 	for i := 0; i < len(artists); i += 4 {
 		row := container.NewHBox()
 		for j := 0; j < 4 && i+j < len(artists); j++ {
 			artist := artists[i+j]
-			card := createArtistCard(state, artist)
+			card := createArtistCard(ctx, state, artist)
 			row.Add(card)
 		}
 		grid.Add(row)
@@ -198,10 +258,10 @@ func createArtistGrid(state *AppState, artists []models.Artist) fyne.CanvasObjec
 }
 
 // createArtistCard fabrique la carte individuelle d'un artiste
-func createArtistCard(state *AppState, artist models.Artist) fyne.CanvasObject {
-	img := state.loadArtistImage(artist.Image)
+func createArtistCard(ctx context.Context, state *AppState, artist models.Artist) fyne.CanvasObject {
+	img := state.loadArtistImage(ctx, artist.Image)
 
-	nameLabel := widget.NewLabel(artist.Name)
+	nameLabel := widget.NewLabel(artistCardTitle(artist, state.failed))
 	nameLabel.Alignment = fyne.TextAlignCenter
 
 	btn := widget.NewButton("Détails", func() {
@@ -221,16 +281,37 @@ func createArtistCard(state *AppState, artist models.Artist) fyne.CanvasObject {
 	return container.NewStack(cardSizeRect, cardBg, container.NewPadded(card))
 }
 
+// mergeArtistsResult remet les artistes en échec dans la liste et indexe leurs erreurs par id
+func mergeArtistsResult(result *api.ArtistsResult) ([]models.Artist, map[int]error) {
+	artists := append([]models.Artist(nil), result.Artists...)
+	failed := make(map[int]error, len(result.Errors))
+	for _, e := range result.Errors {
+		artists = append(artists, e.Artist)
+		failed[e.Artist.Id] = e.Err
+	}
+	sort.SliceStable(artists, func(i, j int) bool { return artists[i].Id < artists[j].Id })
+	return artists, failed
+}
+
+// artistCardTitle signale par un ⚠️ les artistes dont les concerts n'ont pas pu être chargés
+func artistCardTitle(artist models.Artist, failed map[int]error) string {
+	if _, ok := failed[artist.Id]; ok {
+		return "⚠️ " + artist.Name + " (concerts indisponibles)"
+	}
+	return artist.Name
+}
+
 // loadArtistImage charge l'image d'un artiste en arrière-plan avec placeholder
-func (s *AppState) loadArtistImage(imageURL string) fyne.CanvasObject {
-	return LoadImageAsync(imageURL, 260, 260)
+func (s *AppState) loadArtistImage(ctx context.Context, imageURL string) fyne.CanvasObject {
+	return LoadImageAsync(ctx, imageURL, 260, 260)
 }
 
 // displayArtistDetail remplace le contenu par la vue détail d'un artiste
 func displayArtistDetail(state *AppState, artist models.Artist) {
+	ctx := state.newViewContext()
 	state.mainContent.Objects = nil
 
-	detail := CreateArtistDetailView(artist, state.app, func() {
+	detail := CreateArtistDetailView(ctx, artist, state.app, func() {
 		displayArtistGrid(state, state.allArtists)
 	})
 
@@ -240,6 +321,7 @@ func displayArtistDetail(state *AppState, artist models.Artist) {
 
 // displaySearchView affiche la page de recherche textuelle
 func displaySearchView(state *AppState) {
+	ctx := state.newViewContext()
 	state.mainContent.Objects = nil
 
 	titleText := canvas.NewText("🔍 Rechercher", color.NRGBA{R: 255, G: 255, B: 255, A: 255})
@@ -252,7 +334,7 @@ func displaySearchView(state *AppState) {
 	titleHeader := container.NewStack(titleBg, container.NewCenter(titleText))
 
 	searchEntry := widget.NewEntry()
-	searchEntry.SetPlaceHolder("Entrez le nom d'un artiste...")
+	searchEntry.SetPlaceHolder("Chercher artiste, membre, lieu...")
 
 	resultsContainer := container.NewVBox()
 	resultsScroll := container.NewVScroll(resultsContainer)
@@ -261,20 +343,14 @@ func displaySearchView(state *AppState) {
 	searchBtn := widget.NewButton("Rechercher", func() {
 		resultsContainer.Objects = nil
 		query := searchEntry.Text
-		if query == "" {
+		if strings.TrimSpace(query) == "" {
 			resultsContainer.Add(widget.NewLabel("Veuillez entrer un terme de recherche"))
 			resultsContainer.Refresh()
 			return
 		}
 
-		// Filtrer les artistes
-		var results []models.Artist
-		query = strings.ToLower(query)
-		for _, artist := range state.allArtists {
-			if strings.Contains(strings.ToLower(artist.Name), query) {
-				results = append(results, artist)
-			}
-		}
+		// Même moteur que l'accueil : nom, membres, lieux et années
+		results := ApplyFilters(state.allArtists, FilterCriteria{Query: query})
 
 		if len(results) == 0 {
 			resultsContainer.Add(widget.NewLabel("Aucun artiste trouvé"))
@@ -283,7 +359,7 @@ func displaySearchView(state *AppState) {
 		}
 
 		// Afficher les résultats en grille
-		grid := createArtistGrid(state, results)
+		grid := createArtistGrid(ctx, state, results)
 		resultsContainer.Add(grid)
 		resultsContainer.Refresh()
 	})
@@ -309,6 +385,7 @@ func displaySearchView(state *AppState) {
 
 // displayFilterView affiche la vue des filtres
 func displayFilterView(state *AppState) {
+	ctx := state.newViewContext()
 	state.mainContent.Objects = nil
 
 	// Titre
@@ -316,123 +393,61 @@ func displayFilterView(state *AppState) {
 	title.TextStyle.Bold = true
 	title.Alignment = fyne.TextAlignCenter
 
+	form := newFilterForm(state.allArtists)
+
 	// Filtre par année
 	yearLabel := widget.NewLabel("Année de création:")
 	yearLabel.TextStyle.Bold = true
-	yearMinEntry := widget.NewEntry()
-	yearMinEntry.SetPlaceHolder("Min")
-	yearMaxEntry := widget.NewEntry()
-	yearMaxEntry.SetPlaceHolder("Max")
-	yearBox := container.NewHBox(yearMinEntry, widget.NewLabel("à"), yearMaxEntry)
 
 	// Filtre par album
 	albumLabel := widget.NewLabel("Premier album:")
 	albumLabel.TextStyle.Bold = true
-	albumMinEntry := widget.NewEntry()
-	albumMinEntry.SetPlaceHolder("Min")
-	albumMaxEntry := widget.NewEntry()
-	albumMaxEntry.SetPlaceHolder("Max")
-	albumBox := container.NewHBox(albumMinEntry, widget.NewLabel("à"), albumMaxEntry)
 
 	// Filtre par nombre de membres
 	memberLabel := widget.NewLabel("Nombre de membres:")
 	memberLabel.TextStyle.Bold = true
-	memberMinEntry := widget.NewEntry()
-	memberMinEntry.SetPlaceHolder("Min")
-	memberMaxEntry := widget.NewEntry()
-	memberMaxEntry.SetPlaceHolder("Max")
-	memberBox := container.NewHBox(memberMinEntry, widget.NewLabel("à"), memberMaxEntry)
+	memberCountLabel := widget.NewLabel("Exactement:")
+
+	// Filtre par pays et villes de concert
+	countryLabel := widget.NewLabel("Pays et villes de concert:")
+	countryLabel.TextStyle.Bold = true
 
 	// Filtre par localisation
 	locationLabel := widget.NewLabel("Localisation:")
 	locationLabel.TextStyle.Bold = true
-	locationEntry := widget.NewEntry()
-	locationEntry.SetPlaceHolder("Entrez une localisation...")
-
-	// Bouton de recherche
-	filterBtn := widget.NewButton("Appliquer les filtres", func() {
-		// Implémenter la logique de filtrage
-		var results []models.Artist
-
-		for _, artist := range state.allArtists {
-			// Filtrer par année
-			if yearMinEntry.Text != "" || yearMaxEntry.Text != "" {
-				minYear, maxYear := 0, 9999
-				if yearMinEntry.Text != "" {
-					fmt.Sscanf(yearMinEntry.Text, "%d", &minYear)
-				}
-				if yearMaxEntry.Text != "" {
-					fmt.Sscanf(yearMaxEntry.Text, "%d", &maxYear)
-				}
-				if artist.CreationDate < minYear || artist.CreationDate > maxYear {
-					continue
-				}
-			}
-
-			// Filtrer par album
-			if albumMinEntry.Text != "" || albumMaxEntry.Text != "" {
-				minYear, maxYear := 0, 9999
-				if albumMinEntry.Text != "" {
-					fmt.Sscanf(albumMinEntry.Text, "%d", &minYear)
-				}
-				if albumMaxEntry.Text != "" {
-					fmt.Sscanf(albumMaxEntry.Text, "%d", &maxYear)
-				}
-				albumYear := 0
-				fmt.Sscanf(artist.FirstAlbum, "%d", &albumYear)
-				if albumYear < minYear || albumYear > maxYear {
-					continue
-				}
-			}
-
-			// Filtrer par nombre de membres
-			if memberMinEntry.Text != "" || memberMaxEntry.Text != "" {
-				minMembers, maxMembers := 0, 9999
-				if memberMinEntry.Text != "" {
-					fmt.Sscanf(memberMinEntry.Text, "%d", &minMembers)
-				}
-				if memberMaxEntry.Text != "" {
-					fmt.Sscanf(memberMaxEntry.Text, "%d", &maxMembers)
-				}
-				memberCount := len(artist.Members)
-				if memberCount < minMembers || memberCount > maxMembers {
-					continue
-				}
-			}
-
-			// Filtrer par localisation
-			if locationEntry.Text != "" {
-				locationFound := false
-				locQuery := strings.ToLower(locationEntry.Text)
-				for _, loc := range artist.Locations {
-					if strings.Contains(strings.ToLower(loc), locQuery) {
-						locationFound = true
-						break
-					}
-				}
-				if !locationFound {
-					continue
-				}
-			}
-
-			results = append(results, artist)
+	form.locationQuery.SetPlaceHolder("Entrez une localisation...")
+
+	// Résultats affichés sous les filtres ; chaque mise à jour abandonne les images de la grille précédente
+	resultsTitle := widget.NewLabel("")
+	resultsTitle.TextStyle.Bold = true
+	resultsTitle.Alignment = fyne.TextAlignCenter
+	resultsContainer := container.NewVBox()
+	var resultsCancel context.CancelFunc
+
+	showResults := func() {
+		criteria, errs := form.criteria()
+		if errs != nil {
+			return
 		}
+		results := ApplyFilters(state.allArtists, criteria)
 
-		// Afficher les résultats
-		state.mainContent.Objects = nil
-		resultsTitle := widget.NewLabel(fmt.Sprintf("Résultats: %d artiste(s)", len(results)))
-		resultsTitle.TextStyle.Bold = true
-		resultsTitle.Alignment = fyne.TextAlignCenter
+		if resultsCancel != nil {
+			resultsCancel()
+		}
+		var resultsCtx context.Context
+		resultsCtx, resultsCancel = context.WithCancel(ctx)
 
-		state.mainContent.Add(resultsTitle)
+		resultsTitle.SetText(fmt.Sprintf("Résultats: %d artiste(s)", len(results)))
 		if len(results) > 0 {
-			grid := createArtistGrid(state, results)
-			state.mainContent.Add(grid)
+			resultsContainer.Objects = []fyne.CanvasObject{createArtistGrid(resultsCtx, state, results)}
 		} else {
-			state.mainContent.Add(widget.NewLabel("Aucun artiste ne correspond aux filtres"))
+			resultsContainer.Objects = []fyne.CanvasObject{widget.NewLabel("Aucun artiste ne correspond aux filtres")}
 		}
-		state.mainContent.Refresh()
-	})
+		resultsContainer.Refresh()
+	}
+	// Curseurs et cases à cocher mettent les résultats à jour en direct ; le bouton applique la localisation
+	form.onChanged = showResults
+	filterBtn := widget.NewButton("Appliquer les filtres", showResults)
 
 	// Bouton Retour
 	backBtn := widget.NewButton("← Retour", func() {
@@ -443,16 +458,25 @@ func displayFilterView(state *AppState) {
 	filtersScroll := container.NewVScroll(
 		container.NewVBox(
 			yearLabel,
-			yearBox,
+			form.creation,
 			widget.NewSeparator(),
 			albumLabel,
-			albumBox,
+			form.album,
 			widget.NewSeparator(),
 			memberLabel,
-			memberBox,
+			form.members,
+			memberCountLabel,
+			form.memberCounts,
+			widget.NewSeparator(),
+			countryLabel,
+			form.locations.view,
 			widget.NewSeparator(),
 			locationLabel,
-			locationEntry,
+			form.locationQuery,
+			form.errorLabel,
+			widget.NewSeparator(),
+			resultsTitle,
+			resultsContainer,
 		),
 	)
 
@@ -5,8 +5,10 @@ package ui
 
 import (
 	"Groupie-Tracker/models"
+	"context"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -14,9 +16,17 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-// CreateArtistDetailView construit la page détaillée d'un artiste avec image, membres, lieux et bouton retour
-func CreateArtistDetailView(artist models.Artist, app fyne.App, onBack func()) fyne.CanvasObject {
-	img := loadDetailImage(artist.Image)
+const (
+	// artistMapWidth et artistMapHeight sont les dimensions de la carte des concerts
+	artistMapWidth  = 600
+	artistMapHeight = 400
+)
+
+// CreateArtistDetailView construit la page détaillée d'un artiste avec image, membres, lieux et bouton retour.
+// L'image et la carte des concerts sont chargées en arrière-plan : la vue s'affiche immédiatement,
+// et la carte est abandonnée dès que ctx est annulé.
+func CreateArtistDetailView(ctx context.Context, artist models.Artist, app fyne.App, onBack func()) fyne.CanvasObject {
+	img := LoadImageAsync(ctx, artist.Image, 200, 200)
 
 	nameLabel := widget.NewLabel(artist.Name)
 	nameLabel.TextStyle.Bold = true
@@ -40,31 +50,11 @@ func CreateArtistDetailView(artist models.Artist, app fyne.App, onBack func()) f
 	locationsLabel.TextStyle.Bold = true
 	locationsBox := container.NewVBox()
 
-	for _, loc := range artist.Locations {
-		locText := widget.NewLabel("  • " + loc)
+	locationsBox.Add(createArtistMap(ctx, artist, artistMapWidth, artistMapHeight))
+	for i, loc := range artist.Locations {
+		locText := widget.NewLabel(fmt.Sprintf("  %d. %s", i+1, formatLocationWithDates(loc, artist.DatesForLocation(loc))))
 		locText.Wrapping = fyne.TextWrapWord
-
-		mapPlaceholder := widget.NewLabel("🗺️ Chargement...")
-		mapPlaceholder.Alignment = fyne.TextAlignCenter
-
-		locContainer := container.NewVBox(
-			locText,
-			mapPlaceholder,
-		)
-
-		locationsBox.Add(locContainer)
-
-		go func(loc string, idx int) {
-			locMap := createLocationMapForSingle(loc)
-			if len(locationsBox.Objects) > idx {
-				if vbox, ok := locationsBox.Objects[idx].(*fyne.Container); ok && vbox.Layout != nil {
-					if len(vbox.Objects) > 1 {
-						vbox.Objects[1] = locMap
-						vbox.Refresh()
-					}
-				}
-			}
-		}(loc, len(locationsBox.Objects)-1)
+		locationsBox.Add(locText)
 	}
 
 	backButton := widget.NewButtonWithIcon("Retour", theme.NavigateBackIcon(), func() {
@@ -125,3 +115,12 @@ func CreateArtistDetailView(artist models.Artist, app fyne.App, onBack func()) f
 
 	return view
 }
+
+// formatLocationWithDates affiche un lieu suivi de ses dates, ex: "Paris, France — 12-05-2019, 13-05-2019"
+func formatLocationWithDates(loc string, dates []string) string {
+	label := models.ParseLocation(loc).String()
+	if len(dates) == 0 {
+		return label
+	}
+	return label + " — " + strings.Join(dates, ", ")
+}
@@ -0,0 +1,81 @@
+// Package ui - assets.go gère l'emplacement des ressources téléchargées (images, tuiles).
+// Toutes les ressources sont décodées en mémoire et, si besoin, conservées dans un sous-répertoire
+// du cache de l'application ; sans cache utilisateur, un répertoire propre à la session est supprimé à la fermeture.
+package ui
+
+import (
+	"Groupie-Tracker/api"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	sessionDirOnce sync.Once
+	sessionDir     string
+)
+
+// assetDir renvoie le répertoire où ranger les ressources de type kind ("images", "tiles"...),
+// ou "" si aucun emplacement n'est disponible (ressources uniquement en mémoire)
+func assetDir(kind string) string {
+	if dir, err := api.AppCacheDir(); err == nil {
+		return filepath.Join(dir, kind)
+	}
+
+	sessionDirOnce.Do(func() {
+		if dir, err := os.MkdirTemp("", "groupie-tracker-*"); err == nil {
+			sessionDir = dir
+		}
+	})
+	if sessionDir == "" {
+		return ""
+	}
+	return filepath.Join(sessionDir, kind)
+}
+
+// cleanupAssets supprime le répertoire de session ; les répertoires du cache utilisateur sont conservés
+func cleanupAssets() {
+	if sessionDir != "" {
+		os.RemoveAll(sessionDir)
+	}
+}
+
+// pruneDir supprime les fichiers de dir les moins récemment modifiés jusqu'à ce que leur taille
+// totale ne dépasse plus maxBytes, et renvoie la taille restante
+func pruneDir(dir string, maxBytes int64) int64 {
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var (
+		files []file
+		total int64
+	)
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, file{path, info.Size(), info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= maxBytes {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			total -= f.size
+		}
+	}
+	return total
+}
@@ -0,0 +1,157 @@
+// Package ui - concert_map.go affiche la carte de tous les concerts du jeu de données.
+// Chaque lieu distinct reçoit un marqueur dont la taille dépend du nombre de concerts ;
+// un clic liste les artistes qui y ont joué et permet d'ouvrir leur page détaillée.
+package ui
+
+import (
+	"Groupie-Tracker/geo"
+	"Groupie-Tracker/models"
+	"context"
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// concertMapWidth et concertMapHeight sont les dimensions de la carte mondiale
+	concertMapWidth  = 900
+	concertMapHeight = 500
+	// concertMapZoom montre le monde entier dans la carte
+	concertMapZoom = 2
+)
+
+// mapCenterOfWorld centre la carte mondiale un peu au nord de l'équateur, où se trouvent la plupart des lieux
+var mapCenterOfWorld = geo.Point{Lat: 25, Lon: 10}
+
+// concertPlace regroupe les concerts donnés en un même lieu
+type concertPlace struct {
+	location models.Location
+	raw      string // une des chaînes d'origine, pour le géocodage
+	concerts int
+	artists  []models.Artist
+}
+
+// groupConcertPlaces regroupe les lieux de tous les artistes, triés du plus fréquenté au moins fréquenté
+func groupConcertPlaces(artists []models.Artist) []*concertPlace {
+	byKey := make(map[string]*concertPlace)
+	var places []*concertPlace
+
+	for _, artist := range artists {
+		for _, loc := range artist.ParsedLocations() {
+			key := loc.String()
+			place, ok := byKey[key]
+			if !ok {
+				place = &concertPlace{location: loc, raw: loc.Raw}
+				byKey[key] = place
+				places = append(places, place)
+			}
+			// Un lieu sans dates connues compte pour un concert
+			place.concerts += max(len(artist.DatesForLocation(loc.Raw)), 1)
+			place.artists = append(place.artists, artist)
+		}
+	}
+
+	// Les petits marqueurs, posés en dernier, sont dessinés par-dessus les gros et restent cliquables
+	sort.SliceStable(places, func(i, j int) bool { return places[i].concerts > places[j].concerts })
+	return places
+}
+
+// concertMarkerSize calcule un diamètre proportionnel à la racine du nombre de concerts
+func concertMarkerSize(concerts int) float32 {
+	return float32(math.Min(12+4*math.Sqrt(float64(concerts)), 40))
+}
+
+// displayConcertMapView affiche la carte mondiale des concerts
+func displayConcertMapView(state *AppState) {
+	ctx := state.newViewContext()
+	state.mainContent.Objects = nil
+
+	titleText := canvas.NewText("🌍 Carte des concerts", color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	titleText.TextSize = 40
+	titleText.TextStyle.Bold = true
+	titleText.Alignment = fyne.TextAlignCenter
+
+	titleBg := canvas.NewRectangle(color.NRGBA{R: 20, G: 20, B: 20, A: 255})
+	titleBg.SetMinSize(fyne.NewSize(800, 80))
+	titleHeader := container.NewStack(titleBg, container.NewCenter(titleText))
+
+	places := groupConcertPlaces(state.allArtists)
+
+	progress := widget.NewLabel(fmt.Sprintf("🗺️ Géocodage de %d lieux...", len(places)))
+	progress.Alignment = fyne.TextAlignCenter
+
+	sizeRect := canvas.NewRectangle(color.NRGBA{R: 0, G: 0, B: 0, A: 0})
+	sizeRect.SetMinSize(fyne.NewSize(concertMapWidth, concertMapHeight))
+	mapContainer := container.NewStack(sizeRect, progress)
+
+	placeTitle := widget.NewLabel("Cliquez sur un lieu pour voir les artistes qui y ont joué")
+	placeTitle.TextStyle.Bold = true
+	placeArtists := container.NewVBox()
+
+	showPlace := func(place *concertPlace) {
+		placeTitle.SetText(fmt.Sprintf("%s — %d concert(s)", place.location, place.concerts))
+		placeArtists.Objects = nil
+		for _, artist := range place.artists {
+			placeArtists.Add(widget.NewButton(artist.Name, func() {
+				displayArtistDetail(state, artist)
+			}))
+		}
+		placeArtists.Refresh()
+	}
+
+	go loadConcertMap(ctx, places, mapContainer, progress, showPlace)
+
+	backBtn := widget.NewButton("← Retour", func() {
+		displayArtistGrid(state, state.allArtists)
+	})
+
+	state.mainContent.Objects = []fyne.CanvasObject{
+		container.NewVBox(
+			titleHeader,
+			widget.NewSeparator(),
+			container.NewHBox(backBtn),
+			container.NewCenter(mapContainer),
+			widget.NewSeparator(),
+			placeTitle,
+			placeArtists,
+		),
+	}
+	state.mainContent.Refresh()
+}
+
+// loadConcertMap géocode les lieux en arrière-plan puis remplace la progression par la carte
+func loadConcertMap(ctx context.Context, places []*concertPlace, mapContainer *fyne.Container, progress *widget.Label, showPlace func(*concertPlace)) {
+	var markers []mapMarker
+	for i, place := range places {
+		point, ok := geocodeLocation(ctx, place.raw)
+		if ctx.Err() != nil {
+			return
+		}
+		if ok {
+			markers = append(markers, mapMarker{
+				point:   point,
+				tooltip: fmt.Sprintf("%s — %d concert(s)", place.location, place.concerts),
+				size:    concertMarkerSize(place.concerts),
+				onTap:   func() { showPlace(place) },
+			})
+		}
+		if i%10 == 9 {
+			done := i + 1
+			onMain(ctx, func() {
+				progress.SetText(fmt.Sprintf("🗺️ Géocodage des lieux... %d/%d", done, len(places)))
+			})
+		}
+	}
+
+	onMain(ctx, func() {
+		world := newMapWidget(ctx, mapCenterOfWorld, concertMapZoom, fyne.NewSize(concertMapWidth, concertMapHeight), markers)
+		mapContainer.Objects = []fyne.CanvasObject{world}
+		mapContainer.Refresh()
+	})
+}
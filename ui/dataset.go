@@ -0,0 +1,66 @@
+// Package ui - dataset.go charge le jeu de données des artistes pour les différentes vues.
+// Il lit d'abord le cache disque pour un démarrage instantané (y compris hors ligne), puis
+// interroge l'API en arrière-plan quand le cache a expiré et enregistre le nouveau résultat.
+package ui
+
+import (
+	"Groupie-Tracker/api"
+	"Groupie-Tracker/models"
+	"context"
+	"log"
+	"time"
+)
+
+// dataset regroupe les artistes affichables et l'origine des données
+type dataset struct {
+	artists  []models.Artist
+	failed   map[int]error
+	cachedAt time.Time // zéro quand les données viennent directement de l'API
+	stale    bool      // vrai quand le cache a dépassé sa ttl et doit être rafraîchi
+}
+
+// loadDataset renvoie le cache disque s'il existe, sinon interroge l'API
+func loadDataset(ctx context.Context, cache *api.DatasetCache) (*dataset, error) {
+	if cache != nil {
+		if cached, err := cache.Load(); err == nil {
+			artists, failed := mergeArtistsResult(cached.Result())
+			return &dataset{
+				artists:  artists,
+				failed:   failed,
+				cachedAt: cached.FetchedAt,
+				stale:    !cache.Fresh(cached),
+			}, nil
+		}
+	}
+	return fetchDataset(ctx, cache)
+}
+
+// fetchDataset interroge l'API et met le cache disque à jour
+func fetchDataset(ctx context.Context, cache *api.DatasetCache) (*dataset, error) {
+	result, err := api.GetArtists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Les compteurs de revalidation aident à vérifier que les requêtes conditionnelles fonctionnent
+	stats := api.DefaultClient.CacheStats()
+	log.Printf("api: %d artistes chargés, %d en échec ; cache HTTP : %d revalidés (304), %d téléchargés",
+		len(result.Artists), len(result.Errors), stats.Hits, stats.Misses)
+	if cache != nil {
+		// Les artistes en échec gardent leurs détails en cache plutôt que d'être vidés
+		result = cache.RestoreFailed(result)
+		// Un cache non écrit n'empêche pas l'affichage : il sera retenté au prochain chargement
+		_ = cache.Save(result)
+	}
+	artists, failed := mergeArtistsResult(result)
+	return &dataset{artists: artists, failed: failed}, nil
+}
+
+// offlineText décrit des données en cache que l'API n'a pas pu rafraîchir
+func offlineText(cachedAt time.Time) string {
+	return "📴 Hors ligne — données du " + cachedAt.Format("02/01/2006 15:04")
+}
+
+// refreshingText décrit des données en cache en cours de rafraîchissement
+func refreshingText(cachedAt time.Time) string {
+	return "🔄 Données du " + cachedAt.Format("02/01/2006 15:04") + ", mise à jour…"
+}
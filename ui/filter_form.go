@@ -0,0 +1,149 @@
+// Package ui - filter_form.go regroupe les champs de saisie des filtres partagés par l'accueil et la barre latérale.
+// Les intervalles se choisissent avec des curseurs bornés par les artistes chargés, les nombres de membres
+// et les lieux avec des cases à cocher peuplées depuis ces mêmes artistes ; la saisie est convertie
+// en FilterCriteria typés et validée, les erreurs étant signalées au lieu de fausser les résultats.
+package ui
+
+import (
+	"Groupie-Tracker/models"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// fieldLabels donne le libellé affiché pour chaque champ dans les messages d'erreur
+var fieldLabels = map[FilterField]string{
+	FieldCreationMin:    "Année de création (min)",
+	FieldCreationMax:    "Année de création (max)",
+	FieldAlbumMin:       "Premier album (min)",
+	FieldAlbumMax:       "Premier album (max)",
+	FieldMemberCountMin: "Nombre de membres (min)",
+	FieldMemberCountMax: "Nombre de membres (max)",
+}
+
+// filterForm contient les curseurs et champs des filtres ainsi que le résumé des erreurs
+type filterForm struct {
+	creation      *rangeSlider
+	album         *rangeSlider
+	members       *rangeSlider
+	memberCounts  *widget.CheckGroup
+	locations     *locationTree
+	locationQuery *widget.Entry
+	errorLabel    *widget.Label
+
+	// onChanged est appelé à chaque curseur déplacé ou case cochée, pour mettre les résultats à jour en direct
+	onChanged func()
+}
+
+// newFilterForm crée le formulaire, bornes et choix tirés de artists
+func newFilterForm(artists []models.Artist) *filterForm {
+	bounds := ComputeFilterBounds(artists)
+	options := ComputeFilterOptions(artists)
+	f := &filterForm{
+		creation:      newRangeSlider(bounds.CreationYear),
+		album:         newRangeSlider(bounds.FirstAlbumYear),
+		members:       newRangeSlider(bounds.MemberCount),
+		memberCounts:  widget.NewCheckGroup(memberCountLabels(options.MemberCounts), nil),
+		locations:     newLocationTree(options, fyne.NewSize(360, 240)),
+		locationQuery: widget.NewEntry(),
+		errorLabel:    widget.NewLabel(""),
+	}
+	notify := func() {
+		if f.onChanged != nil {
+			f.onChanged()
+		}
+	}
+	for _, s := range []*rangeSlider{f.creation, f.album, f.members} {
+		s.OnChanged = func(int, int) { notify() }
+	}
+	f.memberCounts.Horizontal = true
+	f.memberCounts.OnChanged = func([]string) { notify() }
+	f.locations.onChanged = notify
+	f.errorLabel.Importance = widget.DangerImportance
+	f.errorLabel.Wrapping = fyne.TextWrapWord
+	f.errorLabel.Hide()
+	return f
+}
+
+// setArtists adapte curseurs et choix à un nouveau jeu de données, sans notifier onChanged
+func (f *filterForm) setArtists(artists []models.Artist) {
+	bounds := ComputeFilterBounds(artists)
+	options := ComputeFilterOptions(artists)
+	f.creation.SetBounds(bounds.CreationYear)
+	f.album.SetBounds(bounds.FirstAlbumYear)
+	f.members.SetBounds(bounds.MemberCount)
+
+	// Options et sélection sont remplacées sans passer par OnChanged
+	labels := memberCountLabels(options.MemberCounts)
+	var kept []string
+	for _, sel := range f.memberCounts.Selected {
+		if slices.Contains(labels, sel) {
+			kept = append(kept, sel)
+		}
+	}
+	f.memberCounts.Options = labels
+	f.memberCounts.Selected = kept
+	f.memberCounts.Refresh()
+	f.locations.setOptions(options)
+}
+
+// criteria convertit la saisie en critères typés ; les erreurs sont affichées sous le formulaire
+func (f *filterForm) criteria() (FilterCriteria, FilterErrors) {
+	criteria := FilterCriteria{
+		CreationYear:   f.creation.Selection(),
+		FirstAlbumYear: f.album.Selection(),
+		MemberCount:    f.members.Selection(),
+		LocationQuery:  f.locationQuery.Text,
+	}
+	for _, label := range f.memberCounts.Selected {
+		if n, err := strconv.Atoi(label); err == nil {
+			criteria.MemberCounts = append(criteria.MemberCounts, n)
+		}
+	}
+	sort.Ints(criteria.MemberCounts)
+	criteria.Countries, criteria.Cities = f.locations.selection()
+	errs := criteria.Validate()
+	f.showErrors(errs)
+	return criteria, errs
+}
+
+// showErrors résume les erreurs sous le formulaire
+func (f *filterForm) showErrors(errs FilterErrors) {
+	if len(errs) == 0 {
+		f.errorLabel.Hide()
+		return
+	}
+	lines := make([]string, len(errs))
+	for i, e := range errs {
+		lines[i] = fieldLabels[e.Field] + " : " + e.Message
+	}
+	f.errorLabel.SetText(strings.Join(lines, "\n"))
+	f.errorLabel.Show()
+}
+
+// reset remet les curseurs aux extrémités, vide la localisation et efface les erreurs
+func (f *filterForm) reset() {
+	f.creation.Reset()
+	f.album.Reset()
+	f.members.Reset()
+	onChanged := f.memberCounts.OnChanged
+	f.memberCounts.OnChanged = nil
+	f.memberCounts.SetSelected(nil)
+	f.memberCounts.OnChanged = onChanged
+	f.locations.reset()
+	f.locationQuery.SetText("")
+	f.showErrors(nil)
+}
+
+// memberCountLabels convertit les nombres de membres en libellés de cases à cocher
+func memberCountLabels(counts []int) []string {
+	labels := make([]string, len(counts))
+	for i, n := range counts {
+		labels[i] = strconv.Itoa(n)
+	}
+	return labels
+}
@@ -1,119 +1,259 @@
 // Package ui - filter_logic.go contient la logique métier de filtrage.
-// Il applique les critères de filtrage (dates de création, années d'albums, nombre de membres, localités)
-// de manière indépendante de l'UI. C'est un module testable et réutilisable pour tous les filtres.
+// Il applique les critères de filtrage (recherche texte, dates de création, années d'albums, nombre de membres, pays et villes)
+// de manière indépendante de l'UI. C'est l'unique moteur de filtrage, partagé par toutes les vues.
 package ui
 
 import (
 	"Groupie-Tracker/models"
-	"strconv"
+	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 )
 
-// FilterCriteria contient tous les critères de filtrage
-type FilterCriteria struct {
-	CreationMin    string
-	CreationMax    string
-	AlbumMin       string
-	AlbumMax       string
-	MemberCountMin string
-	MemberCountMax string
-	LocationQuery  string
+// Limites plausibles des bornes saisies ; au-delà, la saisie est refusée plutôt qu'ignorée
+const (
+	minFilterYear    = 1900
+	maxFilterMembers = 30
+)
+
+// FilterField identifie un champ du formulaire de filtres
+type FilterField string
+
+const (
+	FieldCreationMin    FilterField = "CreationMin"
+	FieldCreationMax    FilterField = "CreationMax"
+	FieldAlbumMin       FilterField = "AlbumMin"
+	FieldAlbumMax       FilterField = "AlbumMax"
+	FieldMemberCountMin FilterField = "MemberCountMin"
+	FieldMemberCountMax FilterField = "MemberCountMax"
+)
+
+// FieldError décrit une saisie invalide pour un champ donné
+type FieldError struct {
+	Field   FilterField
+	Message string
 }
 
-// ApplyFilters applique les critères de filtrage sur la liste d'artistes
-func ApplyFilters(artists []models.Artist, criteria FilterCriteria) []models.Artist {
-	filtered := artists
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
 
-	// Filtre année de création
-	if criteria.CreationMin != "" || criteria.CreationMax != "" {
-		var min, max int
-		if criteria.CreationMin != "" {
-			min, _ = strconv.Atoi(criteria.CreationMin)
-		} else {
-			min = 0
+// FilterErrors regroupe les erreurs de validation, champ par champ
+type FilterErrors []FieldError
+
+func (errs FilterErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// For renvoie l'erreur associée à un champ, ou nil si le champ est valide
+func (errs FilterErrors) For(field FilterField) error {
+	for _, e := range errs {
+		if e.Field == field {
+			return e
 		}
-		if criteria.CreationMax != "" {
-			max, _ = strconv.Atoi(criteria.CreationMax)
-		} else {
-			max = 9999
+	}
+	return nil
+}
+
+// IntRange est un intervalle d'entiers dont chaque borne est optionnelle (nil = non bornée)
+type IntRange struct {
+	Min *int
+	Max *int
+}
+
+// IsSet indique si au moins une borne est définie
+func (r IntRange) IsSet() bool {
+	return r.Min != nil || r.Max != nil
+}
+
+// Contains teste si v respecte les bornes définies
+func (r IntRange) Contains(v int) bool {
+	return (r.Min == nil || v >= *r.Min) && (r.Max == nil || v <= *r.Max)
+}
+
+// validate vérifie les limites autorisées et l'ordre des bornes
+func (r IntRange) validate(minField, maxField FilterField, lower, upper int) FilterErrors {
+	var errs FilterErrors
+	outOfRange := fmt.Sprintf("doit être compris entre %d et %d", lower, upper)
+	if r.Min != nil && (*r.Min < lower || *r.Min > upper) {
+		errs = append(errs, FieldError{Field: minField, Message: outOfRange})
+	}
+	if r.Max != nil && (*r.Max < lower || *r.Max > upper) {
+		errs = append(errs, FieldError{Field: maxField, Message: outOfRange})
+	}
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		errs = append(errs, FieldError{Field: minField, Message: "le minimum dépasse le maximum"})
+	}
+	return errs
+}
+
+// Bounds est l'intervalle fermé [Lo, Hi] des valeurs observées dans les données
+type Bounds struct {
+	Lo, Hi int
+}
+
+// include élargit b pour contenir v
+func (b *Bounds) include(v int, first bool) {
+	if first || v < b.Lo {
+		b.Lo = v
+	}
+	if first || v > b.Hi {
+		b.Hi = v
+	}
+}
+
+// FilterBounds donne, pour chaque filtre par intervalle, la plage couverte par les artistes chargés
+type FilterBounds struct {
+	CreationYear   Bounds
+	FirstAlbumYear Bounds
+	MemberCount    Bounds
+}
+
+// ComputeFilterBounds calcule les plages des filtres à partir des artistes ; les premiers albums sans année lisible sont ignorés
+func ComputeFilterBounds(artists []models.Artist) FilterBounds {
+	var fb FilterBounds
+	albumSeen := false
+	for i, a := range artists {
+		fb.CreationYear.include(a.CreationDate, i == 0)
+		fb.MemberCount.include(len(a.Members), i == 0)
+		if year, ok := firstYearFromString(a.FirstAlbum); ok {
+			fb.FirstAlbumYear.include(year, !albumSeen)
+			albumSeen = true
 		}
+	}
+	return fb
+}
 
-		var temp []models.Artist
-		for _, a := range filtered {
-			if a.CreationDate >= min && a.CreationDate <= max {
-				temp = append(temp, a)
+// FilterOptions liste les valeurs proposées par les filtres à choix multiples, tirées des artistes chargés
+type FilterOptions struct {
+	MemberCounts []int               // nombres de membres présents, triés
+	Countries    []string            // pays où un concert a eu lieu, triés
+	Cities       map[string][]string // lieux par pays (forme Location.String()), triés
+}
+
+// ComputeFilterOptions recense les nombres de membres et les lieux de concert des artistes
+func ComputeFilterOptions(artists []models.Artist) FilterOptions {
+	counts := make(map[int]bool)
+	cities := make(map[string]map[string]bool)
+	for _, a := range artists {
+		counts[len(a.Members)] = true
+		for _, loc := range a.ParsedLocations() {
+			if cities[loc.Country] == nil {
+				cities[loc.Country] = make(map[string]bool)
 			}
+			cities[loc.Country][loc.String()] = true
 		}
-		filtered = temp
 	}
 
-	// Filtre année du premier album
-	if criteria.AlbumMin != "" || criteria.AlbumMax != "" {
-		var min, max int
-		if criteria.AlbumMin != "" {
-			min, _ = strconv.Atoi(criteria.AlbumMin)
-		} else {
-			min = 0
+	opts := FilterOptions{Cities: make(map[string][]string, len(cities))}
+	for n := range counts {
+		opts.MemberCounts = append(opts.MemberCounts, n)
+	}
+	sort.Ints(opts.MemberCounts)
+	for country, places := range cities {
+		opts.Countries = append(opts.Countries, country)
+		for place := range places {
+			opts.Cities[country] = append(opts.Cities[country], place)
 		}
-		if criteria.AlbumMax != "" {
-			max, _ = strconv.Atoi(criteria.AlbumMax)
-		} else {
-			max = 9999
+		sort.Strings(opts.Cities[country])
+	}
+	sort.Strings(opts.Countries)
+	return opts
+}
+
+// FilterCriteria contient tous les critères de filtrage ; un ensemble vide ne filtre pas
+type FilterCriteria struct {
+	Query          string
+	CreationYear   IntRange
+	FirstAlbumYear IntRange
+	MemberCount    IntRange
+	MemberCounts   []int    // nombres de membres acceptés, ex: 1, 2, 4 ou 6
+	Countries      []string // pays acceptés, ex: "USA"
+	Cities         []string // lieux acceptés, sous la forme Location.String()
+	LocationQuery  string
+}
+
+// Validate vérifie les bornes des critères et renvoie les erreurs champ par champ (nil si tout est valide)
+func (c FilterCriteria) Validate() FilterErrors {
+	maxYear := time.Now().Year()
+	var errs FilterErrors
+	errs = append(errs, c.CreationYear.validate(FieldCreationMin, FieldCreationMax, minFilterYear, maxYear)...)
+	errs = append(errs, c.FirstAlbumYear.validate(FieldAlbumMin, FieldAlbumMax, minFilterYear, maxYear)...)
+	errs = append(errs, c.MemberCount.validate(FieldMemberCountMin, FieldMemberCountMax, 1, maxFilterMembers)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ApplyFilters applique les critères de filtrage sur la liste d'artistes
+func ApplyFilters(artists []models.Artist, criteria FilterCriteria) []models.Artist {
+	var filtered []models.Artist
+	for _, a := range artists {
+		if criteria.Match(a) {
+			filtered = append(filtered, a)
 		}
+	}
+	return filtered
+}
 
-		var temp []models.Artist
-		for _, a := range filtered {
-			if year, ok := firstYearFromString(a.FirstAlbum); ok {
-				if year >= min && year <= max {
-					temp = append(temp, a)
-				}
-			}
+// Match indique si un artiste satisfait l'ensemble des critères
+func (c FilterCriteria) Match(a models.Artist) bool {
+	// Recherche texte (nom, membres, lieux, années)
+	if q := strings.TrimSpace(strings.ToLower(c.Query)); q != "" && !matchArtist(q, a) {
+		return false
+	}
+
+	// Filtre année de création
+	if !c.CreationYear.Contains(a.CreationDate) {
+		return false
+	}
+
+	// Filtre année du premier album : un artiste sans année lisible est exclu dès qu'une borne est définie
+	if c.FirstAlbumYear.IsSet() {
+		year, ok := firstYearFromString(a.FirstAlbum)
+		if !ok || !c.FirstAlbumYear.Contains(year) {
+			return false
 		}
-		filtered = temp
 	}
 
 	// Filtre nombre de membres
-	if criteria.MemberCountMin != "" || criteria.MemberCountMax != "" {
-		var min, max int
-		if criteria.MemberCountMin != "" {
-			min, _ = strconv.Atoi(criteria.MemberCountMin)
-		} else {
-			min = 0
-		}
-		if criteria.MemberCountMax != "" {
-			max, _ = strconv.Atoi(criteria.MemberCountMax)
-		} else {
-			max = 9999
-		}
+	if !c.MemberCount.Contains(len(a.Members)) {
+		return false
+	}
+	if len(c.MemberCounts) > 0 && !slices.Contains(c.MemberCounts, len(a.Members)) {
+		return false
+	}
 
-		var temp []models.Artist
-		for _, a := range filtered {
-			count := len(a.Members)
-			if count >= min && count <= max {
-				temp = append(temp, a)
+	// Filtre pays / villes : l'artiste doit avoir joué dans au moins un des lieux cochés
+	if len(c.Countries) > 0 || len(c.Cities) > 0 {
+		played := false
+		for _, loc := range a.ParsedLocations() {
+			if slices.Contains(c.Countries, loc.Country) || slices.Contains(c.Cities, loc.String()) {
+				played = true
+				break
 			}
 		}
-		filtered = temp
+		if !played {
+			return false
+		}
 	}
 
 	// Filtre par localisation
-	locQ := strings.TrimSpace(strings.ToLower(criteria.LocationQuery))
-	if locQ != "" {
-		var temp []models.Artist
-		for _, a := range filtered {
-			matched := false
-			for _, loc := range a.Locations {
-				if strings.Contains(strings.ToLower(loc), locQ) {
-					matched = true
-					break
-				}
-			}
-			if matched {
-				temp = append(temp, a)
+	if locQ := strings.TrimSpace(strings.ToLower(c.LocationQuery)); locQ != "" {
+		for _, loc := range a.ParsedLocations() {
+			if loc.Matches(locQ) {
+				return true
 			}
 		}
-		filtered = temp
+		return false
 	}
 
-	return filtered
+	return true
 }
@@ -0,0 +1,153 @@
+// Package ui - geocache.go mémorise sur disque les résultats de géocodage entre les sessions.
+// Chaque requête normalisée ("london, uk") est associée à ses coordonnées, ou marquée introuvable,
+// si bien qu'un lieu déjà vu ne coûte plus aucun appel réseau à Nominatim.
+package ui
+
+import (
+	"Groupie-Tracker/api"
+	"Groupie-Tracker/geo"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// notFoundTTL limite la durée d'un résultat négatif, au cas où Nominatim finirait par connaître le lieu
+const notFoundTTL = 7 * 24 * time.Hour
+
+// geoCacheEntry est le résultat mémorisé d'un géocodage
+type geoCacheEntry struct {
+	geo.Point
+	Found    bool      `json:"found"`
+	CachedAt time.Time `json:"cachedAt"`
+}
+
+// UnmarshalJSON accepte aussi les fichiers des versions précédentes, où lat et lon étaient
+// les chaînes renvoyées telles quelles par Nominatim plutôt que des nombres
+func (e *geoCacheEntry) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Lat      json.Number `json:"lat"`
+		Lon      json.Number `json:"lon"`
+		Found    bool        `json:"found"`
+		CachedAt time.Time   `json:"cachedAt"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = geoCacheEntry{Found: raw.Found, CachedAt: raw.CachedAt}
+	if !raw.Found {
+		return nil
+	}
+	var err error
+	if e.Lat, err = raw.Lat.Float64(); err != nil {
+		return err
+	}
+	e.Lon, err = raw.Lon.Float64()
+	return err
+}
+
+// geoCache est un cache clé → coordonnées, chargé depuis un fichier JSON au premier accès
+type geoCache struct {
+	resolvePath func() string
+	path        string
+	once        sync.Once
+	mu          sync.Mutex // protège entries
+	writeMu     sync.Mutex // sérialise les réécritures du fichier, hors de mu
+	entries     map[string]geoCacheEntry
+}
+
+// sharedGeoCache est le cache de géocodage commun à toutes les vues ;
+// son fichier n'est résolu qu'au premier accès, pas à l'initialisation du paquet
+var sharedGeoCache = newGeoCache(defaultGeoCachePath)
+
+func newGeoCache(resolvePath func() string) *geoCache {
+	return &geoCache{resolvePath: resolvePath, entries: make(map[string]geoCacheEntry)}
+}
+
+// defaultGeoCachePath renvoie le fichier de cache, ou "" pour un cache uniquement en mémoire
+func defaultGeoCachePath() string {
+	dir, err := api.AppCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "geocode.json")
+}
+
+// geoCacheKey normalise une localisation pour que "london-uk" et "London, UK" partagent une entrée
+func geoCacheKey(query string) string {
+	return geo.NormalizeKey(query)
+}
+
+// load résout le chemin du fichier et le lit une seule fois
+func (c *geoCache) load() {
+	c.once.Do(func() {
+		c.path = c.resolvePath()
+		if c.path == "" {
+			return
+		}
+		data, err := os.ReadFile(c.path)
+		if err != nil {
+			return
+		}
+		var entries map[string]geoCacheEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return
+		}
+		c.mu.Lock()
+		for k, v := range entries {
+			c.entries[k] = v
+		}
+		c.mu.Unlock()
+	})
+}
+
+// get renvoie l'entrée mémorisée pour query ; un négatif expiré est ignoré
+func (c *geoCache) get(query string) (geoCacheEntry, bool) {
+	c.load()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[geoCacheKey(query)]
+	if !ok || (!entry.Found && time.Since(entry.CachedAt) > notFoundTTL) {
+		return geoCacheEntry{}, false
+	}
+	return entry, true
+}
+
+// put mémorise un résultat puis réécrit le fichier de cache
+func (c *geoCache) put(query string, entry geoCacheEntry) {
+	c.load()
+	entry.CachedAt = time.Now()
+
+	c.mu.Lock()
+	c.entries[geoCacheKey(query)] = entry
+	c.mu.Unlock()
+
+	c.save()
+}
+
+// save écrit un instantané du cache sans bloquer les lectures pendant l'encodage et l'écriture.
+// L'instantané est pris sous writeMu, si bien qu'une écriture ne remplace jamais un état plus récent.
+func (c *geoCache) save() {
+	if c.path == "" {
+		return
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.mu.Lock()
+	snapshot := maps.Clone(c.entries)
+	c.mu.Unlock()
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return
+	}
+	// Un échec d'écriture n'a pour effet qu'un nouvel appel réseau à la prochaine session
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err == nil {
+		_ = os.Rename(tmp, c.path)
+	}
+}
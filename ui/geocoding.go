@@ -1,59 +1,57 @@
 // Package ui - geocoding.go gère la conversion des noms de localités en coordonnées géographiques.
-// Il utilise l'API Nominatim (OpenStreetMap) pour transformer "Paris-France" en lat/lon.
+// Il transforme "paris-france" en lat/lon via le gazetteer embarqué ou l'API Nominatim (OpenStreetMap).
 // C'est essentiel pour afficher les lieux de concerts sur les cartes à la bonne position.
 package ui
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strings"
+	"Groupie-Tracker/geo"
+	"Groupie-Tracker/models"
+	"context"
+	"errors"
 )
 
-// geocodeLocation interroge Nominatim pour convertir une adresse en lat/lon
-func geocodeLocation(query string) (lat string, lon string, ok bool) {
-	norm := normalizeLocationQuery(query)
-	endpoint := fmt.Sprintf("https://nominatim.openstreetmap.org/search?q=%s&format=json&limit=1", url.QueryEscape(norm))
-	req, err := http.NewRequest("GET", endpoint, nil)
-	if err != nil {
-		return "", "", false
-	}
-	req.Header.Set("User-Agent", "GroupieTracker/1.0")
+// geocoder est le backend de géocodage partagé, remplaçable via SetGeocoder
+var geocoder = defaultGeocoder()
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", "", false
-	}
-	defer resp.Body.Close()
+// geocoderAuthoritative indique si un ErrNotFound de geocoder est définitif et peut être mis en cache :
+// la chaîne par défaut se termine par Nominatim, mais un gazetteer seul ignore simplement les lieux absents
+var geocoderAuthoritative = true
 
-	if resp.StatusCode != http.StatusOK {
-		return "", "", false
+// defaultGeocoder consulte d'abord le gazetteer embarqué, puis Nominatim pour les lieux inconnus
+func defaultGeocoder() geo.Geocoder {
+	nominatim := geo.NewNominatim()
+	if gazetteer, err := geo.DefaultGazetteer(); err == nil {
+		return geo.Chain(gazetteer, nominatim)
 	}
+	return nominatim
+}
 
-	var results []struct {
-		Lat string `json:"lat"`
-		Lon string `json:"lon"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
-		return "", "", false
-	}
-	if len(results) == 0 {
-		return "", "", false
-	}
-	return results[0].Lat, results[0].Lon, true
+// SetGeocoder remplace le backend de géocodage utilisé par les cartes.
+// authoritative indique si ErrNotFound signifie que le lieu est réellement inconnu (service en ligne
+// complet) : sinon les lieux introuvables ne sont pas mémorisés, pour être redemandés à un autre backend.
+func SetGeocoder(g geo.Geocoder, authoritative bool) {
+	geocoder = g
+	geocoderAuthoritative = authoritative
 }
 
-// normalizeLocationQuery nettoie une localisation "ville-pays" ou avec underscores en "ville, pays"
-func normalizeLocationQuery(loc string) string {
-	parts := strings.FieldsFunc(loc, func(r rune) bool {
-		return r == '-' || r == '_'
-	})
-	if len(parts) == 0 {
-		return strings.TrimSpace(loc)
+// geocodeLocation convertit une adresse en position, via le cache disque puis le backend (annulable via ctx)
+func geocodeLocation(ctx context.Context, query string) (geo.Point, bool) {
+	if entry, cached := sharedGeoCache.get(query); cached {
+		return entry.Point, entry.Found
 	}
-	for i := range parts {
-		parts[i] = strings.TrimSpace(parts[i])
+
+	point, err := geocoder.Geocode(ctx, models.ParseLocation(query).String())
+	switch {
+	case err == nil:
+		sharedGeoCache.put(query, geoCacheEntry{Point: point, Found: true})
+		return point, true
+	case errors.Is(err, geo.ErrNotFound):
+		if geocoderAuthoritative {
+			sharedGeoCache.put(query, geoCacheEntry{Found: false})
+		}
+		return geo.Point{}, false
+	default:
+		// Une erreur réseau n'est pas mise en cache : le lieu sera redemandé plus tard
+		return geo.Point{}, false
 	}
-	return strings.Join(parts, ", ")
 }
@@ -6,10 +6,13 @@ package ui
 import (
 	"Groupie-Tracker/api"
 	"Groupie-Tracker/models"
+	"context"
 	"fmt"
 	"image/color"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -18,41 +21,51 @@ import (
 )
 
 type homeState struct {
-	allArtists     []models.Artist
-	filtered       []models.Artist
-	cards          *fyne.Container
-	suggestions    []Suggestion
-	list           *widget.List
-	listWrap       *container.Scroll
-	searchEntry    *widget.Entry
-	app            fyne.App
-	filterLabel    *widget.Label
-	creationMin    *widget.Entry
-	creationMax    *widget.Entry
-	albumMin       *widget.Entry
-	albumMax       *widget.Entry
-	memberCountMin *widget.Entry
-	memberCountMax *widget.Entry
-	mainContainer  *fyne.Container
-	window         fyne.Window
-	homeView       fyne.CanvasObject
-	locationQuery  *widget.Entry
+	allArtists    []models.Artist
+	filtered      []models.Artist
+	cards         *fyne.Container
+	suggestions   []Suggestion
+	list          *widget.List
+	listWrap      *container.Scroll
+	searchEntry   *widget.Entry
+	app           fyne.App
+	filterLabel   *widget.Label
+	filters       *filterForm
+	advanced      FilterCriteria
+	mainContainer *fyne.Container
+	window        fyne.Window
+	homeView      fyne.CanvasObject
+	ctx           context.Context
+	detailCancel  context.CancelFunc
+	cardsCancel   context.CancelFunc
+	failed        map[int]error
+	statusNote    string
 }
 
 // Home construit la page d'accueil avec recherche, suggestions et filtres
 func Home(app fyne.App, window fyne.Window) fyne.CanvasObject {
-	artists, err := api.GetArtists()
+	// Fermer la fenêtre annule toutes les requêtes encore en cours
+	ctx, cancel := context.WithCancel(context.Background())
+	window.SetOnClosed(cancel)
+
+	// Un cache indisponible n'est pas bloquant : on se contente de l'API
+	cache, _ := api.DefaultDatasetCache()
+	data, err := loadDataset(ctx, cache)
 	if err != nil {
 		return widget.NewLabel("Erreur lors du chargement: " + err.Error())
 	}
 
 	state := &homeState{
-		allArtists: artists,
-		filtered:   artists,
+		allArtists: data.artists,
+		filtered:   data.artists,
 		app:        app,
 		window:     window,
+		ctx:        ctx,
+		failed:     data.failed,
 	}
 
+	state.filters = newFilterForm(data.artists)
+	state.filters.onChanged = func() { state.applyAdvancedFilters() }
 	state.cards = container.NewVBox()
 	state.renderCards()
 
@@ -115,72 +128,62 @@ func Home(app fyne.App, window fyne.Window) fyne.CanvasObject {
 	state.homeView = content
 	state.mainContainer = container.NewStack(content)
 
+	if data.stale {
+		state.refreshDataset(cache, data.cachedAt)
+	}
+
 	return state.mainContainer
 }
 
+// refreshDataset recharge les artistes depuis l'API en arrière-plan, en signalant l'état du cache
+func (s *homeState) refreshDataset(cache *api.DatasetCache, cachedAt time.Time) {
+	s.statusNote = refreshingText(cachedAt)
+	s.updateFilterLabel()
+
+	runAsync(s.ctx, func(ctx context.Context) (*dataset, error) {
+		return fetchDataset(ctx, cache)
+	}, func(data *dataset, err error) {
+		if err != nil {
+			s.statusNote = offlineText(cachedAt)
+			s.updateFilterLabel()
+			return
+		}
+		s.statusNote = ""
+		s.allArtists = data.artists
+		s.failed = data.failed
+		s.filters.setArtists(s.allArtists)
+		if advanced, errs := s.filters.criteria(); errs == nil {
+			s.advanced = advanced
+		}
+		s.applySearch(s.searchEntry.Text)
+	})
+}
+
 // showAdvancedFilters ouvre une fenêtre pour affiner la recherche
 func (s *homeState) showAdvancedFilters() {
 	filterWindow := s.app.NewWindow("Filtres avances")
 	filterWindow.Resize(fyne.NewSize(400, 500))
 
+	// Curseurs et cases à cocher mettent la grille à jour en direct ; le bouton applique la localisation
 	creationLabel := widget.NewLabel("Année de creation:")
-	s.creationMin = widget.NewEntry()
-	s.creationMin.SetPlaceHolder("Min")
-	s.creationMax = widget.NewEntry()
-	s.creationMax.SetPlaceHolder("Max")
-	creationBox := container.NewHBox(
-		widget.NewLabel("De"),
-		s.creationMin,
-		widget.NewLabel("à"),
-		s.creationMax,
-	)
-
 	albumLabel := widget.NewLabel("Année du premier album:")
-	s.albumMin = widget.NewEntry()
-	s.albumMin.SetPlaceHolder("Min")
-	s.albumMax = widget.NewEntry()
-	s.albumMax.SetPlaceHolder("Max")
-	albumBox := container.NewHBox(
-		widget.NewLabel("De"),
-		s.albumMin,
-		widget.NewLabel("à"),
-		s.albumMax,
-	)
-
 	memberLabel := widget.NewLabel("Nombre de membres:")
-	s.memberCountMin = widget.NewEntry()
-	s.memberCountMin.SetPlaceHolder("Min")
-	s.memberCountMax = widget.NewEntry()
-	s.memberCountMax.SetPlaceHolder("Max")
-	memberBox := container.NewHBox(
-		widget.NewLabel("De"),
-		s.memberCountMin,
-		widget.NewLabel("à"),
-		s.memberCountMax,
-	)
+	memberCountLabel := widget.NewLabel("Exactement:")
+	countryLabel := widget.NewLabel("Pays et villes de concert:")
 
 	locationLabel := widget.NewLabel("Localisation contient:")
-	s.locationQuery = widget.NewEntry()
-	s.locationQuery.SetPlaceHolder("Ex: Paris, France")
+	s.filters.locationQuery.SetPlaceHolder("Ex: Paris, France")
 
+	// Une saisie invalide garde la fenêtre ouverte, erreurs affichées sous le formulaire
 	applyButton := widget.NewButton("Appliquer filtres", func() {
-		s.applyAdvancedFilters()
-		filterWindow.Close()
+		if s.applyAdvancedFilters() {
+			filterWindow.Close()
+		}
 	})
 
 	resetButton := widget.NewButton("Reinitialiser", func() {
-		s.creationMin.SetText("")
-		s.creationMax.SetText("")
-		s.albumMin.SetText("")
-		s.albumMax.SetText("")
-		s.memberCountMin.SetText("")
-		s.memberCountMax.SetText("")
-		if s.locationQuery != nil {
-			s.locationQuery.SetText("")
-		}
-
+		s.filters.reset()
 		s.applyAdvancedFilters()
-
 		filterWindow.Close()
 	})
 
@@ -188,16 +191,22 @@ func (s *homeState) showAdvancedFilters() {
 
 	content := container.NewVBox(
 		creationLabel,
-		creationBox,
+		s.filters.creation,
 		widget.NewSeparator(),
 		albumLabel,
-		albumBox,
+		s.filters.album,
 		widget.NewSeparator(),
 		memberLabel,
-		memberBox,
+		s.filters.members,
+		memberCountLabel,
+		s.filters.memberCounts,
+		widget.NewSeparator(),
+		countryLabel,
+		s.filters.locations.view,
 		widget.NewSeparator(),
 		locationLabel,
-		s.locationQuery,
+		s.filters.locationQuery,
+		s.filters.errorLabel,
 		widget.NewSeparator(),
 		buttonBox,
 	)
@@ -207,29 +216,31 @@ func (s *homeState) showAdvancedFilters() {
 	filterWindow.Show()
 }
 
-// applyAdvancedFilters applique tous les filtres saisis et rafraichit la grille
-func (s *homeState) applyAdvancedFilters() {
-	criteria := FilterCriteria{
-		CreationMin:    s.creationMin.Text,
-		CreationMax:    s.creationMax.Text,
-		AlbumMin:       s.albumMin.Text,
-		AlbumMax:       s.albumMax.Text,
-		MemberCountMin: s.memberCountMin.Text,
-		MemberCountMax: s.memberCountMax.Text,
-	}
-	if s.locationQuery != nil {
-		criteria.LocationQuery = s.locationQuery.Text
-	}
+// criteria combine la recherche en cours avec les derniers filtres avancés validés
+func (s *homeState) criteria() FilterCriteria {
+	criteria := s.advanced
+	criteria.Query = s.searchEntry.Text
+	return criteria
+}
 
-	s.filtered = ApplyFilters(s.allArtists, criteria)
+// applyAdvancedFilters valide les filtres saisis puis rafraichit la grille ;
+// renvoie false si la saisie est invalide, les filtres précédents restant alors en vigueur
+func (s *homeState) applyAdvancedFilters() bool {
+	advanced, errs := s.filters.criteria()
+	if errs != nil {
+		return false
+	}
+	s.advanced = advanced
+	s.filtered = ApplyFilters(s.allArtists, s.criteria())
 	s.renderCards()
 	s.updateFilterLabel()
+	return true
 }
 
-// applySearch filtre et suggère à partir du texte tapé
+// applySearch filtre et suggère à partir du texte tapé, en conservant les filtres avancés
 func (s *homeState) applySearch(q string) {
 	query := strings.TrimSpace(q)
-	s.filtered = SearchArtists(query, s.allArtists)
+	s.filtered = ApplyFilters(s.allArtists, s.criteria())
 	s.suggestions = BuildSuggestions(query, s.allArtists)
 	if len(s.suggestions) == 0 {
 		s.listWrap.Hide()
@@ -245,19 +256,30 @@ func (s *homeState) applySearch(q string) {
 func (s *homeState) updateFilterLabel() {
 	count := len(s.filtered)
 	label := fmt.Sprintf("Artistes affiches (%d)", count)
-	if s.locationQuery != nil {
-		if strings.TrimSpace(s.locationQuery.Text) != "" {
-			label += " • filtre lieu: " + strings.TrimSpace(s.locationQuery.Text)
-		}
+	if locQ := strings.TrimSpace(s.advanced.LocationQuery); locQ != "" {
+		label += " • filtre lieu: " + locQ
+	}
+	if places := append(slices.Clone(s.advanced.Countries), s.advanced.Cities...); len(places) > 0 {
+		label += " • joué à: " + strings.Join(places, " / ")
+	}
+	if s.statusNote != "" {
+		label += " • " + s.statusNote
 	}
 	if s.filterLabel != nil {
 		s.filterLabel.SetText(label)
 	}
 }
 
-// renderCards reconstruit la grille d'artistes
+// renderCards reconstruit la grille d'artistes ; les images encore en cours
+// de chargement pour la grille précédente sont abandonnées
 func (s *homeState) renderCards() {
 	const cardsPerRow = 4
+	if s.cardsCancel != nil {
+		s.cardsCancel()
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.cardsCancel = cancel
+
 	var rows []fyne.CanvasObject
 
 	for i := 0; i < len(s.filtered); i += cardsPerRow {
@@ -268,7 +290,7 @@ func (s *homeState) renderCards() {
 		}
 
 		for j := i; j < end; j++ {
-			card := s.createArtistCard(s.filtered[j])
+			card := s.createArtistCard(ctx, s.filtered[j])
 			rowCards = append(rowCards, card)
 		}
 
@@ -281,10 +303,10 @@ func (s *homeState) renderCards() {
 }
 
 // createArtistCard fabrique une carte cliquable pour un artiste
-func (s *homeState) createArtistCard(artist models.Artist) fyne.CanvasObject {
-	img := s.loadArtistImage(artist.Image)
+func (s *homeState) createArtistCard(ctx context.Context, artist models.Artist) fyne.CanvasObject {
+	img := s.loadArtistImage(ctx, artist.Image)
 
-	nameLabel := widget.NewLabel(artist.Name)
+	nameLabel := widget.NewLabel(artistCardTitle(artist, s.failed))
 	nameLabel.TextStyle.Bold = true
 	nameLabel.Alignment = fyne.TextAlignCenter
 
@@ -306,13 +328,20 @@ func (s *homeState) createArtistCard(artist models.Artist) fyne.CanvasObject {
 }
 
 // loadArtistImage charge l'image d'un artiste en arrière-plan avec un placeholder
-func (s *homeState) loadArtistImage(imageURL string) fyne.CanvasObject {
-	return LoadImageAsync(imageURL, 160, 160)
+func (s *homeState) loadArtistImage(ctx context.Context, imageURL string) fyne.CanvasObject {
+	return LoadImageAsync(ctx, imageURL, 160, 160)
 }
 
 // showArtistDetail remplace la vue courante par les détails de l'artiste
 func (s *homeState) showArtistDetail(artist models.Artist) {
-	detailView := CreateArtistDetailView(artist, s.app, func() {
+	if s.detailCancel != nil {
+		s.detailCancel()
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.detailCancel = cancel
+
+	detailView := CreateArtistDetailView(ctx, artist, s.app, func() {
+		cancel()
 		if s.homeView != nil && s.window != nil {
 			s.window.SetContent(s.homeView)
 		}
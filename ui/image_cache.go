@@ -0,0 +1,224 @@
+// Package ui - image_cache.go partage les images téléchargées entre toutes les vues.
+// Les images décodées restent en mémoire (LRU) et leurs octets bruts sur disque, indexés par URL
+// et bornés en taille (les fichiers les moins récemment utilisés sont supprimés) :
+// reconstruire la grille après une recherche ne coûte donc aucun appel réseau.
+package ui
+
+import (
+	"Groupie-Tracker/api"
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// imageCacheCapacity borne le nombre d'images décodées gardées en mémoire
+	imageCacheCapacity = 128
+	// imageDiskCacheSize borne la taille des images conservées sur disque
+	imageDiskCacheSize = 50 << 20
+)
+
+// imageCache est un cache d'images à deux niveaux : mémoire puis disque
+type imageCache struct {
+	resolveDir func() string
+	dirOnce    sync.Once
+	dir        string // "" pour un cache uniquement en mémoire
+	capacity   int
+	maxDisk    int64
+
+	mu       sync.Mutex
+	disk     int64      // taille des fichiers du répertoire dir, connue après le premier nettoyage
+	measured bool       // disk a été mesuré par pruneDir
+	pruning  bool       // un nettoyage du répertoire est en cours
+	lru      *list.List // du plus récent au plus ancien
+	entries  map[string]*list.Element
+	inflight map[string]*imageCall
+}
+
+// imageCacheEntry est un élément de la liste LRU
+type imageCacheEntry struct {
+	url string
+	img image.Image
+}
+
+// imageCall est un téléchargement partagé entre les demandeurs d'une même URL
+type imageCall struct {
+	done chan struct{}
+	img  image.Image
+	err  error
+}
+
+// sharedImages est le cache d'images commun à toutes les vues ; son répertoire n'est résolu
+// qu'au premier accès disque, pas à l'initialisation du paquet
+var sharedImages = newImageCache(func() string { return assetDir("images") }, imageCacheCapacity, imageDiskCacheSize)
+
+// newImageCache crée un cache gardant capacity images en mémoire et au plus maxDisk octets
+// dans le répertoire renvoyé par resolveDir
+func newImageCache(resolveDir func() string, capacity int, maxDisk int64) *imageCache {
+	return &imageCache{
+		resolveDir: resolveDir,
+		capacity:   capacity,
+		maxDisk:    maxDisk,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+		inflight:   make(map[string]*imageCall),
+	}
+}
+
+// diskDir résout le répertoire du cache disque une seule fois
+func (c *imageCache) diskDir() string {
+	c.dirOnce.Do(func() { c.dir = c.resolveDir() })
+	return c.dir
+}
+
+// cached renvoie l'image décodée si elle est déjà en mémoire, sans accès disque ni réseau
+func (c *imageCache) cached(url string) (image.Image, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[url]; ok {
+		c.lru.MoveToFront(elem)
+		return elem.Value.(imageCacheEntry).img, true
+	}
+	return nil, false
+}
+
+// get renvoie l'image de url depuis la mémoire, le disque ou le réseau ; les demandes
+// simultanées pour une même URL partagent un seul téléchargement
+func (c *imageCache) get(ctx context.Context, url string) (image.Image, error) {
+	if img, ok := c.cached(url); ok {
+		return img, nil
+	}
+
+	c.mu.Lock()
+	call, shared := c.inflight[url]
+	if !shared {
+		call = &imageCall{done: make(chan struct{})}
+		c.inflight[url] = call
+	}
+	c.mu.Unlock()
+
+	if shared {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-call.done:
+			return call.img, call.err
+		}
+	}
+
+	// Le téléchargement partagé ne dépend pas de l'abandon du premier demandeur
+	call.img, call.err = c.load(context.WithoutCancel(ctx), url)
+	c.mu.Lock()
+	delete(c.inflight, url)
+	if call.err == nil {
+		c.add(url, call.img)
+	}
+	c.mu.Unlock()
+	close(call.done)
+	return call.img, call.err
+}
+
+// load lit l'image sur disque, ou la télécharge et l'y enregistre
+func (c *imageCache) load(ctx context.Context, url string) (image.Image, error) {
+	path := c.path(url)
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			if img, _, err := image.Decode(bytes.NewReader(data)); err == nil {
+				// La date de modification sert d'ordre LRU pour pruneDir
+				now := time.Now()
+				_ = os.Chtimes(path, now, now)
+				return img, nil
+			}
+		}
+	}
+
+	data, err := downloadImage(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		// Un échec d'écriture n'a pour effet qu'un nouveau téléchargement à la prochaine session
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil && os.WriteFile(path, data, 0o644) == nil {
+			c.recordWrite(int64(len(data)))
+		}
+	}
+	return img, nil
+}
+
+// recordWrite comptabilise un fichier écrit et allège le répertoire au premier écrit de la session,
+// puis chaque fois que la taille maximale est dépassée. Le parcours du répertoire se fait hors de c.mu
+// pour ne pas bloquer les autres vues, et un seul nettoyage tourne à la fois.
+func (c *imageCache) recordWrite(size int64) {
+	c.mu.Lock()
+	c.disk += size
+	if c.pruning || (c.measured && c.disk <= c.maxDisk) {
+		c.mu.Unlock()
+		return
+	}
+	c.pruning = true
+	c.mu.Unlock()
+
+	total := pruneDir(c.diskDir(), c.maxDisk)
+
+	c.mu.Lock()
+	c.disk = total
+	c.measured = true
+	c.pruning = false
+	c.mu.Unlock()
+}
+
+// add insère une image en mémoire et évince la moins récemment utilisée ; c.mu doit être tenu
+func (c *imageCache) add(url string, img image.Image) {
+	if elem, ok := c.entries[url]; ok {
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[url] = c.lru.PushFront(imageCacheEntry{url: url, img: img})
+	for c.lru.Len() > c.capacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(imageCacheEntry).url)
+	}
+}
+
+// path renvoie le fichier disque associé à url, ou "" sans cache disque
+func (c *imageCache) path(url string) string {
+	dir := c.diskDir()
+	if dir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, hex.EncodeToString(sum[:]))
+}
+
+// downloadImage télécharge les octets bruts d'une image
+func downloadImage(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := api.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -1,13 +1,12 @@
 // Package ui - image_loader.go fournit le chargement asynchrone des images avec placeholder.
-// Il télécharge les images des artistes en arrière-plan tandis qu'un placeholder s'affiche.
+// Il récupère les images des artistes via le cache partagé, en arrière-plan, tandis qu'un placeholder s'affiche.
 // C'est crucial pour maintenir une UI réactive même lors du téléchargement d'images volumineuses.
 package ui
 
 import (
+	"context"
+	"image"
 	"image/color"
-	"io"
-	"net/http"
-	"os"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -15,76 +14,44 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-// LoadImageAsync charge une image de manière asynchrone avec un placeholder
-func LoadImageAsync(imageURL string, width, height float32) fyne.CanvasObject {
-	placeholder := widget.NewLabel("⏳")
-	placeholder.Alignment = fyne.TextAlignCenter
-
+// LoadImageAsync charge une image de manière asynchrone avec un placeholder.
+// Une image déjà en mémoire dans le cache partagé est affichée immédiatement ; le résultat
+// d'un téléchargement est ignoré si ctx est annulé entre-temps (vue remplacée).
+func LoadImageAsync(ctx context.Context, imageURL string, width, height float32) fyne.CanvasObject {
 	sizeRect := canvas.NewRectangle(color.NRGBA{R: 0, G: 0, B: 0, A: 0})
 	sizeRect.SetMinSize(fyne.NewSize(width, height))
 
-	imgContainer := container.NewStack(sizeRect, placeholder)
-
-	go func() {
-		resp, err := http.Get(imageURL)
-		if err != nil {
-			return
-		}
-		defer resp.Body.Close()
+	if img, ok := sharedImages.cached(imageURL); ok {
+		return container.NewStack(sizeRect, newCachedImage(img, width, height))
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			return
-		}
+	placeholder := widget.NewLabel("⏳")
+	placeholder.Alignment = fyne.TextAlignCenter
 
-		tmpFile, err := os.CreateTemp("", "artist-*.jpg")
-		if err != nil {
-			return
-		}
-		defer tmpFile.Close()
+	imgContainer := container.NewStack(sizeRect, placeholder)
+	if imageURL == "" {
+		placeholder.SetText("Image indisponible")
+		return imgContainer
+	}
 
-		_, err = io.Copy(tmpFile, resp.Body)
+	runAsync(ctx, func(ctx context.Context) (image.Image, error) {
+		return sharedImages.get(ctx, imageURL)
+	}, func(img image.Image, err error) {
 		if err != nil {
+			placeholder.SetText("Erreur de chargement")
 			return
 		}
-
-		img := canvas.NewImageFromFile(tmpFile.Name())
-		img.FillMode = canvas.ImageFillContain
-		img.SetMinSize(fyne.NewSize(width, height))
-
-		go func() {
-			imgContainer.Objects = []fyne.CanvasObject{sizeRect, img}
-			imgContainer.Refresh()
-		}()
-	}()
+		imgContainer.Objects = []fyne.CanvasObject{sizeRect, newCachedImage(img, width, height)}
+		imgContainer.Refresh()
+	})
 
 	return imgContainer
 }
 
-// loadDetailImage télécharge et affiche l'image principale d'un artiste
-func loadDetailImage(imageURL string) fyne.CanvasObject {
-	if imageURL == "" {
-		return widget.NewLabel("Image indisponible")
-	}
-
-	resp, err := http.Get(imageURL)
-	if err != nil {
-		return widget.NewLabel("Erreur de chargement")
-	}
-	defer resp.Body.Close()
-
-	tmpFile, err := os.CreateTemp("", "artist-detail-*.jpg")
-	if err != nil {
-		return widget.NewLabel("Erreur de cache")
-	}
-	defer tmpFile.Close()
-
-	_, err = io.Copy(tmpFile, resp.Body)
-	if err != nil {
-		return widget.NewLabel("Erreur de copie")
-	}
-
-	img := canvas.NewImageFromFile(tmpFile.Name())
-	img.FillMode = canvas.ImageFillContain
-	img.SetMinSize(fyne.NewSize(200, 200))
-	return img
+// newCachedImage affiche une image décodée à la taille demandée
+func newCachedImage(img image.Image, width, height float32) *canvas.Image {
+	canvasImg := canvas.NewImageFromImage(img)
+	canvasImg.FillMode = canvas.ImageFillContain
+	canvasImg.SetMinSize(fyne.NewSize(width, height))
+	return canvasImg
 }
@@ -0,0 +1,155 @@
+// Package ui - location_tree.go fournit un arbre pays → lieux à cases à cocher pour filtrer par lieu de concert.
+// Cocher un pays retient tous ses lieux ; cocher un lieu seul ne retient que celui-ci.
+package ui
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Préfixes des identifiants de nœuds : un lieu réduit à son pays aurait sinon le même nom que le pays
+const (
+	countryNodePrefix = "country:"
+	cityNodePrefix    = "city:"
+)
+
+// locationTree est un arbre de sélection multiple des pays et lieux de concert
+type locationTree struct {
+	tree      *widget.Tree
+	view      fyne.CanvasObject
+	options   FilterOptions
+	countryOf map[string]string // pays de chaque lieu proposé
+	countries map[string]bool
+	cities    map[string]bool
+
+	// onChanged est appelé à chaque case cochée ou décochée
+	onChanged func()
+}
+
+// newLocationTree crée l'arbre des lieux proposés par options, affiché sur size
+func newLocationTree(options FilterOptions, size fyne.Size) *locationTree {
+	t := &locationTree{
+		countries: make(map[string]bool),
+		cities:    make(map[string]bool),
+	}
+	t.setOptions(options)
+	t.tree = widget.NewTree(t.childIDs, t.isBranch, t.createNode, t.updateNode)
+
+	sizeRect := canvas.NewRectangle(nil)
+	sizeRect.SetMinSize(size)
+	t.view = container.NewStack(sizeRect, t.tree)
+	return t
+}
+
+// setOptions remplace les lieux proposés ; les coches qui n'existent plus sont retirées
+func (t *locationTree) setOptions(options FilterOptions) {
+	t.options = options
+	t.countryOf = make(map[string]string)
+	for country, cities := range options.Cities {
+		for _, city := range cities {
+			t.countryOf[city] = country
+		}
+	}
+	for country := range t.countries {
+		if !slices.Contains(options.Countries, country) {
+			delete(t.countries, country)
+		}
+	}
+	for city := range t.cities {
+		if _, ok := t.countryOf[city]; !ok {
+			delete(t.cities, city)
+		}
+	}
+	if t.tree != nil {
+		t.tree.Refresh()
+	}
+}
+
+// selection renvoie les pays et lieux cochés, triés
+func (t *locationTree) selection() (countries, cities []string) {
+	for country := range t.countries {
+		countries = append(countries, country)
+	}
+	for city := range t.cities {
+		cities = append(cities, city)
+	}
+	sort.Strings(countries)
+	sort.Strings(cities)
+	return countries, cities
+}
+
+// reset décoche tout, sans notifier onChanged
+func (t *locationTree) reset() {
+	t.countries = make(map[string]bool)
+	t.cities = make(map[string]bool)
+	t.tree.Refresh()
+}
+
+func (t *locationTree) childIDs(id widget.TreeNodeID) []widget.TreeNodeID {
+	var ids []widget.TreeNodeID
+	switch {
+	case id == "":
+		for _, country := range t.options.Countries {
+			ids = append(ids, countryNodePrefix+country)
+		}
+	case strings.HasPrefix(id, countryNodePrefix):
+		for _, city := range t.options.Cities[strings.TrimPrefix(id, countryNodePrefix)] {
+			ids = append(ids, cityNodePrefix+city)
+		}
+	}
+	return ids
+}
+
+func (t *locationTree) isBranch(id widget.TreeNodeID) bool {
+	return id == "" || strings.HasPrefix(id, countryNodePrefix)
+}
+
+func (t *locationTree) createNode(bool) fyne.CanvasObject {
+	return widget.NewCheck("", nil)
+}
+
+// updateNode affiche la case d'un pays ou d'un lieu ; un lieu dont le pays est coché apparaît coché et grisé
+func (t *locationTree) updateNode(id widget.TreeNodeID, _ bool, obj fyne.CanvasObject) {
+	check := obj.(*widget.Check)
+	check.OnChanged = nil
+
+	if country, ok := strings.CutPrefix(id, countryNodePrefix); ok {
+		check.SetText(country)
+		check.SetChecked(t.countries[country])
+		check.Enable()
+		check.OnChanged = func(on bool) { t.toggle(t.countries, country, on) }
+		return
+	}
+
+	city := strings.TrimPrefix(id, cityNodePrefix)
+	country := t.countryOf[city]
+	check.SetText(strings.TrimSuffix(city, ", "+country))
+	if t.countries[country] {
+		check.SetChecked(true)
+		check.Disable()
+		return
+	}
+	check.SetChecked(t.cities[city])
+	check.Enable()
+	check.OnChanged = func(on bool) { t.toggle(t.cities, city, on) }
+}
+
+// toggle coche ou décoche key dans set puis notifie le changement
+func (t *locationTree) toggle(set map[string]bool, key string, on bool) {
+	if on {
+		set[key] = true
+	} else {
+		delete(set, key)
+	}
+	// Les lieux d'un pays (dé)coché changent d'apparence
+	t.tree.Refresh()
+	if t.onChanged != nil {
+		t.onChanged()
+	}
+}
@@ -0,0 +1,149 @@
+// Package ui - map_pin.go définit le marqueur numéroté posé sur les cartes de concerts.
+// Survoler (ou toucher) un marqueur affiche une infobulle avec le lieu et les dates du concert.
+package ui
+
+import (
+	"image/color"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// pinSize est le diamètre par défaut d'un marqueur, en pixels
+const pinSize = 22
+
+// mapPin est un marqueur numéroté avec infobulle
+type mapPin struct {
+	widget.BaseWidget
+	number  int
+	tooltip string
+	fill    color.Color
+	diam    float32
+	onTap   func()
+	popup   *widget.PopUp
+}
+
+var (
+	_ fyne.Tappable     = (*mapPin)(nil)
+	_ desktop.Hoverable = (*mapPin)(nil)
+)
+
+// newMapPin crée un marqueur affichant number, dont l'infobulle contient tooltip
+func newMapPin(number int, tooltip string) *mapPin {
+	p := &mapPin{
+		number:  number,
+		tooltip: tooltip,
+		fill:    color.NRGBA{R: 220, G: 40, B: 60, A: 255},
+		diam:    pinSize,
+	}
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+// CreateRenderer dessine un disque coloré portant le numéro du marqueur
+func (p *mapPin) CreateRenderer() fyne.WidgetRenderer {
+	circle := canvas.NewCircle(p.fill)
+	circle.StrokeColor = color.White
+	circle.StrokeWidth = 2
+
+	label := ""
+	if p.number > 0 {
+		label = strconv.Itoa(p.number)
+	}
+	text := canvas.NewText(label, color.White)
+	text.TextSize = 11
+	text.TextStyle.Bold = true
+	text.Alignment = fyne.TextAlignCenter
+
+	return &mapPinRenderer{pin: p, circle: circle, text: text}
+}
+
+// MinSize renvoie le diamètre du marqueur
+func (p *mapPin) MinSize() fyne.Size {
+	return fyne.NewSize(p.diam, p.diam)
+}
+
+// Tapped exécute l'action du marqueur, ou bascule l'infobulle pour les écrans tactiles sans survol
+func (p *mapPin) Tapped(*fyne.PointEvent) {
+	if p.onTap != nil {
+		p.onTap()
+		return
+	}
+	if p.popup != nil && p.popup.Visible() {
+		p.hideTooltip()
+		return
+	}
+	p.showTooltip()
+}
+
+// MouseIn affiche l'infobulle au survol
+func (p *mapPin) MouseIn(*desktop.MouseEvent) {
+	p.showTooltip()
+}
+
+// MouseMoved ne fait rien : l'infobulle reste en place pendant le survol
+func (p *mapPin) MouseMoved(*desktop.MouseEvent) {}
+
+// MouseOut masque l'infobulle
+func (p *mapPin) MouseOut() {
+	p.hideTooltip()
+}
+
+// showTooltip ouvre l'infobulle juste à droite du marqueur
+func (p *mapPin) showTooltip() {
+	if p.tooltip == "" {
+		return
+	}
+	c := fyne.CurrentApp().Driver().CanvasForObject(p)
+	if c == nil {
+		return
+	}
+	if p.popup == nil {
+		label := widget.NewLabel(p.tooltip)
+		p.popup = widget.NewPopUp(label, c)
+	}
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(p)
+	p.popup.ShowAtPosition(pos.Add(fyne.NewPos(p.Size().Width+4, 0)))
+}
+
+// hideTooltip ferme l'infobulle si elle est ouverte
+func (p *mapPin) hideTooltip() {
+	if p.popup != nil {
+		p.popup.Hide()
+	}
+}
+
+type mapPinRenderer struct {
+	pin    *mapPin
+	circle *canvas.Circle
+	text   *canvas.Text
+}
+
+func (r *mapPinRenderer) Layout(size fyne.Size) {
+	r.circle.Resize(size)
+	r.circle.Move(fyne.NewPos(0, 0))
+	textSize := r.text.MinSize()
+	r.text.Resize(fyne.NewSize(size.Width, textSize.Height))
+	r.text.Move(fyne.NewPos(0, (size.Height-textSize.Height)/2))
+}
+
+func (r *mapPinRenderer) MinSize() fyne.Size {
+	return r.pin.MinSize()
+}
+
+func (r *mapPinRenderer) Refresh() {
+	r.circle.FillColor = r.pin.fill
+	r.circle.Refresh()
+	r.text.Refresh()
+}
+
+func (r *mapPinRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.circle, r.text}
+}
+
+func (r *mapPinRenderer) Destroy() {
+	r.pin.hideTooltip()
+}
@@ -0,0 +1,305 @@
+// Package ui - map_widget.go fournit une carte interactive construite sur les tuiles OSM.
+// Glisser déplace la carte, la molette zoome autour du curseur et un double-clic zoome sur le point visé ;
+// seules les tuiles visibles sont téléchargées, au fur et à mesure que la vue change.
+package ui
+
+import (
+	"Groupie-Tracker/geo"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// minWidgetZoom et maxWidgetZoom bornent le zoom de la carte interactive
+	minWidgetZoom = 1
+	maxWidgetZoom = 17
+	// maxLoadedTiles borne le nombre de tuiles décodées gardées en mémoire par une carte
+	maxLoadedTiles = 256
+	// maxTileDownloads borne les téléchargements de tuiles simultanés d'une carte
+	maxTileDownloads = 4
+)
+
+// mapBackground est la couleur affichée sous les tuiles pas encore chargées
+var mapBackground = color.NRGBA{R: 170, G: 211, B: 223, A: 255}
+
+// tileKey identifie une tuile OSM
+type tileKey struct {
+	z, x, y int
+}
+
+// mapWidget est une carte glissable et zoomable portant des marqueurs
+type mapWidget struct {
+	widget.BaseWidget
+
+	ctx     context.Context
+	center  geo.Point
+	zoom    int
+	minSize fyne.Size
+	markers []mapMarker
+
+	tiles     map[tileKey]image.Image
+	requested map[tileKey]bool
+	downloads chan struct{}
+}
+
+var (
+	_ fyne.Draggable      = (*mapWidget)(nil)
+	_ fyne.Scrollable     = (*mapWidget)(nil)
+	_ fyne.DoubleTappable = (*mapWidget)(nil)
+)
+
+// newMapWidget crée une carte centrée sur center au zoom donné ; ctx borne les téléchargements de tuiles
+func newMapWidget(ctx context.Context, center geo.Point, zoom int, minSize fyne.Size, markers []mapMarker) *mapWidget {
+	m := &mapWidget{
+		ctx:       ctx,
+		center:    center,
+		zoom:      clampZoom(zoom),
+		minSize:   minSize,
+		markers:   markers,
+		tiles:     make(map[tileKey]image.Image),
+		requested: make(map[tileKey]bool),
+		downloads: make(chan struct{}, maxTileDownloads),
+	}
+	m.ExtendBaseWidget(m)
+	return m
+}
+
+// CreateRenderer crée le rendu de la carte
+func (m *mapWidget) CreateRenderer() fyne.WidgetRenderer {
+	r := &mapWidgetRenderer{m: m}
+	r.raster = canvas.NewRaster(r.draw)
+	for _, marker := range m.markers {
+		pin := newMapPin(marker.number, marker.tooltip)
+		if marker.size > 0 {
+			pin.diam = marker.size
+		}
+		pin.onTap = marker.onTap
+		r.pins = append(r.pins, pin)
+	}
+	return r
+}
+
+// MinSize renvoie la taille demandée à la création
+func (m *mapWidget) MinSize() fyne.Size {
+	return m.minSize
+}
+
+// Dragged déplace la carte de la distance parcourue par la souris
+func (m *mapWidget) Dragged(ev *fyne.DragEvent) {
+	cx, cy := latLonToPixel(m.center.Lat, m.center.Lon, m.zoom)
+	m.center = pixelToLatLon(cx-float64(ev.Dragged.DX), cy-float64(ev.Dragged.DY), m.zoom)
+	m.Refresh()
+}
+
+// DragEnd ne fait rien : la position finale est déjà appliquée par Dragged
+func (m *mapWidget) DragEnd() {}
+
+// Scrolled zoome d'un niveau par cran de molette, en gardant fixe le point sous le curseur
+func (m *mapWidget) Scrolled(ev *fyne.ScrollEvent) {
+	switch {
+	case ev.Scrolled.DY > 0:
+		m.zoomAround(ev.Position, m.zoom+1)
+	case ev.Scrolled.DY < 0:
+		m.zoomAround(ev.Position, m.zoom-1)
+	}
+}
+
+// DoubleTapped zoome d'un niveau et centre la carte sur le point visé
+func (m *mapWidget) DoubleTapped(ev *fyne.PointEvent) {
+	m.center = m.pointAt(ev.Position)
+	m.setZoom(m.zoom + 1)
+}
+
+// zoomAround change le zoom en gardant le point géographique sous pos au même endroit à l'écran
+func (m *mapWidget) zoomAround(pos fyne.Position, zoom int) {
+	zoom = clampZoom(zoom)
+	if zoom == m.zoom {
+		return
+	}
+	anchor := m.pointAt(pos)
+	ax, ay := latLonToPixel(anchor.Lat, anchor.Lon, zoom)
+	size := m.Size()
+	m.center = pixelToLatLon(
+		ax-float64(pos.X)+float64(size.Width)/2,
+		ay-float64(pos.Y)+float64(size.Height)/2,
+		zoom,
+	)
+	m.setZoom(zoom)
+}
+
+// setZoom applique un nouveau zoom
+func (m *mapWidget) setZoom(zoom int) {
+	m.zoom = clampZoom(zoom)
+	m.Refresh()
+}
+
+// evictTiles libère les tuiles les plus éloignées de la vue quand plus de maxLoadedTiles sont en mémoire ;
+// les tuiles des autres niveaux de zoom partent en premier. Une tuile libérée sera retéléchargée
+// (depuis le cache disque) si elle redevient visible.
+func (m *mapWidget) evictTiles() {
+	excess := len(m.tiles) - maxLoadedTiles
+	if excess <= 0 {
+		return
+	}
+	cx, cy := latLonToPixel(m.center.Lat, m.center.Lon, m.zoom)
+	distance := func(key tileKey) float64 {
+		if key.z != m.zoom {
+			return math.Inf(1)
+		}
+		dx := math.Abs(float64(key.x) + 0.5 - cx/tileSize)
+		dy := math.Abs(float64(key.y) + 0.5 - cy/tileSize)
+		return max(dx, dy)
+	}
+
+	keys := make([]tileKey, 0, len(m.tiles))
+	for key := range m.tiles {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return distance(keys[i]) > distance(keys[j]) })
+	for _, key := range keys[:excess] {
+		delete(m.tiles, key)
+		delete(m.requested, key)
+	}
+}
+
+// pointAt renvoie la position géographique affichée à pos, relative au widget
+func (m *mapWidget) pointAt(pos fyne.Position) geo.Point {
+	left, top := m.viewOrigin()
+	return pixelToLatLon(left+float64(pos.X), top+float64(pos.Y), m.zoom)
+}
+
+// viewOrigin renvoie les coordonnées pixel du monde du coin supérieur gauche de la vue
+func (m *mapWidget) viewOrigin() (left, top float64) {
+	cx, cy := latLonToPixel(m.center.Lat, m.center.Lon, m.zoom)
+	size := m.Size()
+	return cx - float64(size.Width)/2, cy - float64(size.Height)/2
+}
+
+// requestTile lance le téléchargement d'une tuile absente, une seule fois
+func (m *mapWidget) requestTile(key tileKey) {
+	if m.requested[key] {
+		return
+	}
+	m.requested[key] = true
+
+	go func() {
+		select {
+		case m.downloads <- struct{}{}:
+		case <-m.ctx.Done():
+			return
+		}
+		img, err := fetchTile(m.ctx, key.z, key.x, key.y)
+		<-m.downloads
+
+		onMain(m.ctx, func() {
+			if err != nil {
+				// Une tuile en échec pourra être redemandée quand elle redeviendra visible
+				delete(m.requested, key)
+				return
+			}
+			m.tiles[key] = img
+			m.evictTiles()
+			if key.z == m.zoom {
+				m.Refresh()
+			}
+		})
+	}()
+}
+
+// clampZoom borne zoom aux niveaux autorisés
+func clampZoom(zoom int) int {
+	return max(minWidgetZoom, min(maxWidgetZoom, zoom))
+}
+
+type mapWidgetRenderer struct {
+	m      *mapWidget
+	raster *canvas.Raster
+	pins   []*mapPin
+}
+
+// draw compose l'image de la vue courante à partir des tuiles déjà chargées, et demande les autres
+func (r *mapWidgetRenderer) draw(_, _ int) image.Image {
+	m := r.m
+	size := m.Size()
+	width, height := int(math.Ceil(float64(size.Width))), int(math.Ceil(float64(size.Height)))
+	if width <= 0 || height <= 0 {
+		return image.NewRGBA(image.Rect(0, 0, 1, 1))
+	}
+
+	view := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(view, view.Bounds(), image.NewUniform(mapBackground), image.Point{}, draw.Src)
+
+	// Grille de tuiles autour de la tuile centrale, assez large pour couvrir toute la vue
+	left, top := m.viewOrigin()
+	n := 1 << m.zoom
+	centerX, centerY := latLonToTile(m.center.Lat, m.center.Lon, m.zoom)
+	spanX := width/(2*tileSize) + 2
+	spanY := height/(2*tileSize) + 2
+
+	for ty := max(centerY-spanY, 0); ty <= min(centerY+spanY, n-1); ty++ {
+		for tx := centerX - spanX; tx <= centerX+spanX; tx++ {
+			offset := image.Pt(int(float64(tx*tileSize)-left), int(float64(ty*tileSize)-top))
+			if !image.Rect(0, 0, tileSize, tileSize).Add(offset).Overlaps(view.Bounds()) {
+				continue
+			}
+			// Les tuiles se répètent horizontalement de part et d'autre de l'antiméridien
+			key := tileKey{z: m.zoom, x: ((tx % n) + n) % n, y: ty}
+			tile, ok := m.tiles[key]
+			if !ok {
+				m.requestTile(key)
+				continue
+			}
+			draw.Draw(view, tile.Bounds().Add(offset), tile, tile.Bounds().Min, draw.Src)
+		}
+	}
+	return view
+}
+
+func (r *mapWidgetRenderer) Layout(size fyne.Size) {
+	r.raster.Resize(size)
+	r.raster.Move(fyne.NewPos(0, 0))
+
+	left, top := r.m.viewOrigin()
+	for i, pin := range r.pins {
+		marker := r.m.markers[i]
+		px, py := latLonToPixel(marker.point.Lat, marker.point.Lon, r.m.zoom)
+		x, y := float32(px-left), float32(py-top)
+
+		// Les marqueurs hors de la vue sont masqués pour ne pas déborder sur les widgets voisins
+		half := pin.diam / 2
+		if x < half || y < half || x > size.Width-half || y > size.Height-half {
+			pin.Hide()
+			continue
+		}
+		pin.Resize(pin.MinSize())
+		pin.Move(fyne.NewPos(x-half, y-half))
+		pin.Show()
+	}
+}
+
+func (r *mapWidgetRenderer) MinSize() fyne.Size {
+	return r.m.MinSize()
+}
+
+func (r *mapWidgetRenderer) Refresh() {
+	r.Layout(r.m.Size())
+	r.raster.Refresh()
+}
+
+func (r *mapWidgetRenderer) Objects() []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{r.raster}
+	for _, pin := range r.pins {
+		objects = append(objects, pin)
+	}
+	return objects
+}
+
+func (r *mapWidgetRenderer) Destroy() {}
@@ -1,16 +1,15 @@
 // Package ui - mapping.go génère les cartes OpenStreetMap pour afficher les lieux de concert.
-// Il convertit les coordonnées géographiques en tuiles de cartes et les affiche avec zoom approprié.
+// Il convertit les coordonnées en pixels et tuiles Web Mercator et cadre la carte sur les lieux géocodés.
 // C'est le module essentiel pour la visualisation spatiale des événements musicaux.
 package ui
 
 import (
+	"Groupie-Tracker/geo"
+	"Groupie-Tracker/models"
+	"context"
 	"fmt"
 	"image/color"
-	"io"
 	"math"
-	"net/http"
-	"os"
-	"strconv"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -55,63 +54,119 @@ func createMapPlaceholder(locations []string) fyne.CanvasObject {
 	return container.NewStack(rect, container.NewCenter(label))
 }
 
-// createLocationMapForSingle récupère une tuile OSM pour un lieu unique et l'affiche
-func createLocationMapForSingle(location string) fyne.CanvasObject {
-	placeholder := widget.NewLabel("🗺️ Chargement...")
-	placeholder.Alignment = fyne.TextAlignCenter
+const (
+	// tileSize est la taille en pixels d'une tuile OSM
+	tileSize = 256
+	// maxMapZoom borne le zoom d'une carte composée, pour garder du contexte autour d'un lieu unique
+	maxMapZoom = 10
+	// mapPadding laisse une marge autour des marqueurs extrêmes
+	mapPadding = 30
+)
 
-	sizeRect := canvas.NewRectangle(color.NRGBA{R: 0, G: 0, B: 0, A: 0})
-	sizeRect.SetMinSize(fyne.NewSize(300, 200))
+// mapMarker est un lieu géocodé à placer sur une carte
+type mapMarker struct {
+	point   geo.Point
+	number  int     // numéro affiché dans le marqueur, 0 pour aucun
+	tooltip string  // texte de l'infobulle
+	size    float32 // diamètre du marqueur, pinSize si nul
+	onTap   func()  // action au clic, infobulle à défaut
+}
 
-	cont := container.NewStack(sizeRect, placeholder)
+// latLonToPixel convertit des lat/lon en coordonnées pixel du monde au zoom z (Web Mercator)
+func latLonToPixel(lat, lon float64, z int) (px, py float64) {
+	worldSize := tileSize * math.Pow(2, float64(z))
+	latRad := lat * math.Pi / 180.0
 
-	go func() {
-		lat, lon, ok := geocodeLocation(location)
-		if !ok {
-			return
-		}
+	px = (lon + 180.0) / 360.0 * worldSize
+	py = (1.0 - math.Log(math.Tan(latRad)+1.0/math.Cos(latRad))/math.Pi) / 2.0 * worldSize
+	return px, py
+}
 
-		clat, _ := strconv.ParseFloat(lat, 64)
-		clon, _ := strconv.ParseFloat(lon, 64)
-		z := 12
-		x, y := latLonToTile(clat, clon, z)
+// pixelToLatLon est l'inverse de latLonToPixel
+func pixelToLatLon(px, py float64, z int) geo.Point {
+	worldSize := tileSize * math.Pow(2, float64(z))
+	lon := px/worldSize*360.0 - 180.0
+	latRad := math.Atan(math.Sinh(math.Pi * (1 - 2*py/worldSize)))
+	return geo.Point{Lat: latRad * 180.0 / math.Pi, Lon: lon}
+}
 
-		mapURL := fmt.Sprintf(
-			"https://tile.openstreetmap.org/%d/%d/%d.png",
-			z, x, y,
-		)
+// fitZoom renvoie le plus grand zoom auquel tous les points tiennent dans une vue width × height
+func fitZoom(points []geo.Point, width, height float64) int {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		x, y := latLonToPixel(p.Lat, p.Lon, 0)
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
 
-		req, err := http.NewRequest("GET", mapURL, nil)
-		if err != nil {
-			return
+	for z := maxMapZoom; z > 0; z-- {
+		scale := math.Pow(2, float64(z))
+		if (maxX-minX)*scale <= width-2*mapPadding && (maxY-minY)*scale <= height-2*mapPadding {
+			return z
 		}
-		req.Header.Set("User-Agent", "GroupieTracker/1.0 (+https://github.com/)")
+	}
+	return 0
+}
 
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil || resp.StatusCode != http.StatusOK {
-			return
-		}
-		defer resp.Body.Close()
+// pointsCenter renvoie le centre, en pixels au zoom z, de la boîte englobant les points
+func pointsCenter(points []geo.Point, z int) (cx, cy float64) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		x, y := latLonToPixel(p.Lat, p.Lon, z)
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+	return (minX + maxX) / 2, (minY + maxY) / 2
+}
 
-		tmpFile, err := os.CreateTemp("", "map-loc-*.png")
-		if err != nil {
-			return
+// createArtistMap construit une carte interactive montrant tous les lieux de concert d'un artiste,
+// cadrée pour les afficher tous ; chaque marqueur porte le numéro du lieu et ses dates en infobulle.
+func createArtistMap(ctx context.Context, artist models.Artist, width, height float32) fyne.CanvasObject {
+	placeholder := widget.NewLabel("🗺️ Chargement de la carte...")
+	placeholder.Alignment = fyne.TextAlignCenter
+
+	sizeRect := canvas.NewRectangle(color.NRGBA{R: 0, G: 0, B: 0, A: 0})
+	sizeRect.SetMinSize(fyne.NewSize(width, height))
+
+	cont := container.NewStack(sizeRect, placeholder)
+
+	go func() {
+		var markers []mapMarker
+		var points []geo.Point
+		for i, loc := range artist.Locations {
+			point, ok := geocodeLocation(ctx, loc)
+			if ctx.Err() != nil {
+				return
+			}
+			if !ok {
+				continue
+			}
+			markers = append(markers, mapMarker{
+				point:   point,
+				number:  i + 1,
+				tooltip: formatLocationWithDates(loc, artist.DatesForLocation(loc)),
+			})
+			points = append(points, point)
 		}
-		defer tmpFile.Close()
 
-		_, err = io.Copy(tmpFile, resp.Body)
-		if err != nil {
+		if len(markers) == 0 {
+			onMain(ctx, func() {
+				cont.Objects = []fyne.CanvasObject{createMapPlaceholder(artist.Locations)}
+				cont.Refresh()
+			})
 			return
 		}
 
-		mapImg := canvas.NewImageFromFile(tmpFile.Name())
-		mapImg.FillMode = canvas.ImageFillContain
-		mapImg.SetMinSize(fyne.NewSize(300, 200))
+		z := fitZoom(points, float64(width), float64(height))
+		cx, cy := pointsCenter(points, z)
+		center := pixelToLatLon(cx, cy, z)
 
-		go func() {
-			cont.Objects = []fyne.CanvasObject{mapImg}
+		onMain(ctx, func() {
+			cont.Objects = []fyne.CanvasObject{newMapWidget(ctx, center, z, fyne.NewSize(width, height), markers)}
 			cont.Refresh()
-		}()
+		})
 	}()
 
 	return cont
@@ -0,0 +1,244 @@
+// Package ui - range_slider.go définit un curseur à deux poignées pour choisir un intervalle d'entiers.
+// Les limites viennent des artistes chargés : l'utilisateur voit d'emblée la plage disponible
+// au lieu de taper des années au hasard. Glisser ou toucher la piste déplace la poignée la plus proche.
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// sliderHandleSize est le diamètre des poignées, en pixels
+	sliderHandleSize = 18
+	// sliderTrackHeight est l'épaisseur de la piste
+	sliderTrackHeight = 4
+	// sliderMinWidth est la largeur minimale du curseur
+	sliderMinWidth = 240
+)
+
+// sliderHandle identifie la poignée en cours de déplacement
+type sliderHandle int
+
+const (
+	handleNone sliderHandle = iota
+	handleLow
+	handleHigh
+)
+
+// rangeSlider sélectionne un intervalle [low, high] compris entre les limites min et max
+type rangeSlider struct {
+	widget.BaseWidget
+	min, max  int
+	low, high int
+	active    sliderHandle
+
+	// OnChanged est appelé à chaque déplacement d'une poignée
+	OnChanged func(low, high int)
+}
+
+var (
+	_ fyne.Draggable = (*rangeSlider)(nil)
+	_ fyne.Tappable  = (*rangeSlider)(nil)
+)
+
+// newRangeSlider crée un curseur couvrant bounds, poignées aux extrémités
+func newRangeSlider(bounds Bounds) *rangeSlider {
+	s := &rangeSlider{}
+	s.ExtendBaseWidget(s)
+	s.SetBounds(bounds)
+	return s
+}
+
+// SetBounds change les limites du curseur ; une poignée laissée à l'extrémité y reste,
+// les autres sont ramenées dans les nouvelles limites
+func (s *rangeSlider) SetBounds(bounds Bounds) {
+	atMin, atMax := s.low == s.min, s.high == s.max
+	s.min, s.max = bounds.Lo, bounds.Hi
+	if atMin {
+		s.low = s.min
+	}
+	if atMax {
+		s.high = s.max
+	}
+	s.low = min(max(s.low, s.min), s.max)
+	s.high = min(max(s.high, s.low), s.max)
+	s.Refresh()
+}
+
+// Reset remet les poignées aux extrémités, sans notifier OnChanged
+func (s *rangeSlider) Reset() {
+	s.low, s.high = s.min, s.max
+	s.Refresh()
+}
+
+// Selection renvoie l'intervalle choisi ; une poignée laissée à l'extrémité ne borne pas le filtre,
+// afin de ne pas exclure les artistes dont la valeur est inconnue
+func (s *rangeSlider) Selection() IntRange {
+	var r IntRange
+	if s.low > s.min {
+		low := s.low
+		r.Min = &low
+	}
+	if s.high < s.max {
+		high := s.high
+		r.Max = &high
+	}
+	return r
+}
+
+// CreateRenderer dessine la piste, la portion sélectionnée, les deux poignées et les valeurs
+func (s *rangeSlider) CreateRenderer() fyne.WidgetRenderer {
+	track := canvas.NewRectangle(theme.Color(theme.ColorNameInputBorder))
+	track.CornerRadius = sliderTrackHeight / 2
+	active := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+	lowHandle := canvas.NewCircle(theme.Color(theme.ColorNamePrimary))
+	lowHandle.StrokeColor = color.White
+	lowHandle.StrokeWidth = 2
+	highHandle := canvas.NewCircle(theme.Color(theme.ColorNamePrimary))
+	highHandle.StrokeColor = color.White
+	highHandle.StrokeWidth = 2
+	values := canvas.NewText("", theme.Color(theme.ColorNameForeground))
+	values.Alignment = fyne.TextAlignCenter
+
+	r := &rangeSliderRenderer{s: s, track: track, active: active, lowHandle: lowHandle, highHandle: highHandle, values: values}
+	r.Refresh()
+	return r
+}
+
+// Dragged déplace la poignée saisie au début du geste
+func (s *rangeSlider) Dragged(ev *fyne.DragEvent) {
+	if s.active == handleNone {
+		s.active = s.nearestHandle(ev.Position.X - ev.Dragged.DX)
+	}
+	s.moveActive(ev.Position.X)
+}
+
+// DragEnd relâche la poignée
+func (s *rangeSlider) DragEnd() {
+	s.active = handleNone
+}
+
+// Tapped amène la poignée la plus proche à la position touchée
+func (s *rangeSlider) Tapped(ev *fyne.PointEvent) {
+	s.active = s.nearestHandle(ev.Position.X)
+	s.moveActive(ev.Position.X)
+	s.active = handleNone
+}
+
+// nearestHandle choisit la poignée la plus proche de x ; à égalité, le côté du geste départage
+func (s *rangeSlider) nearestHandle(x float32) sliderHandle {
+	lowX, highX := s.valueToX(s.low), s.valueToX(s.high)
+	if s.low == s.high {
+		if x < lowX {
+			return handleLow
+		}
+		return handleHigh
+	}
+	if math.Abs(float64(x-lowX)) <= math.Abs(float64(x-highX)) {
+		return handleLow
+	}
+	return handleHigh
+}
+
+// moveActive place la poignée active sur la valeur correspondant à x, sans croiser l'autre poignée
+func (s *rangeSlider) moveActive(x float32) {
+	v := s.xToValue(x)
+	low, high := s.low, s.high
+	switch s.active {
+	case handleLow:
+		low = min(v, s.high)
+	case handleHigh:
+		high = max(v, s.low)
+	default:
+		return
+	}
+	if low == s.low && high == s.high {
+		return
+	}
+	s.low, s.high = low, high
+	s.Refresh()
+	if s.OnChanged != nil {
+		s.OnChanged(low, high)
+	}
+}
+
+// trackWidth est la longueur utile de la piste, entre les centres des poignées aux extrémités
+func (s *rangeSlider) trackWidth() float32 {
+	return max(s.Size().Width-sliderHandleSize, 0)
+}
+
+// valueToX convertit une valeur en abscisse du centre de poignée
+func (s *rangeSlider) valueToX(v int) float32 {
+	if s.max <= s.min {
+		return sliderHandleSize / 2
+	}
+	return sliderHandleSize/2 + float32(v-s.min)/float32(s.max-s.min)*s.trackWidth()
+}
+
+// xToValue convertit une abscisse en valeur entière, bornée aux limites
+func (s *rangeSlider) xToValue(x float32) int {
+	w := s.trackWidth()
+	if s.max <= s.min || w == 0 {
+		return s.min
+	}
+	ratio := float64((x - sliderHandleSize/2) / w)
+	v := s.min + int(math.Round(ratio*float64(s.max-s.min)))
+	return min(max(v, s.min), s.max)
+}
+
+type rangeSliderRenderer struct {
+	s          *rangeSlider
+	track      *canvas.Rectangle
+	active     *canvas.Rectangle
+	lowHandle  *canvas.Circle
+	highHandle *canvas.Circle
+	values     *canvas.Text
+}
+
+func (r *rangeSliderRenderer) Layout(size fyne.Size) {
+	textHeight := r.values.MinSize().Height
+	r.values.Resize(fyne.NewSize(size.Width, textHeight))
+	r.values.Move(fyne.NewPos(0, 0))
+
+	centerY := textHeight + sliderHandleSize/2
+	r.track.Resize(fyne.NewSize(r.s.trackWidth(), sliderTrackHeight))
+	r.track.Move(fyne.NewPos(sliderHandleSize/2, centerY-sliderTrackHeight/2))
+
+	lowX, highX := r.s.valueToX(r.s.low), r.s.valueToX(r.s.high)
+	r.active.Resize(fyne.NewSize(highX-lowX, sliderTrackHeight))
+	r.active.Move(fyne.NewPos(lowX, centerY-sliderTrackHeight/2))
+
+	handle := fyne.NewSize(sliderHandleSize, sliderHandleSize)
+	r.lowHandle.Resize(handle)
+	r.lowHandle.Move(fyne.NewPos(lowX-sliderHandleSize/2, centerY-sliderHandleSize/2))
+	r.highHandle.Resize(handle)
+	r.highHandle.Move(fyne.NewPos(highX-sliderHandleSize/2, centerY-sliderHandleSize/2))
+}
+
+func (r *rangeSliderRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(sliderMinWidth, r.values.MinSize().Height+sliderHandleSize)
+}
+
+func (r *rangeSliderRenderer) Refresh() {
+	if r.s.low == r.s.high {
+		r.values.Text = fmt.Sprintf("%d", r.s.low)
+	} else {
+		r.values.Text = fmt.Sprintf("%d — %d", r.s.low, r.s.high)
+	}
+	r.values.Refresh()
+	r.Layout(r.s.Size())
+	canvas.Refresh(r.s)
+}
+
+func (r *rangeSliderRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.track, r.active, r.lowHandle, r.highHandle, r.values}
+}
+
+func (r *rangeSliderRenderer) Destroy() {}
@@ -0,0 +1,31 @@
+// Package ui - scheduler.go ramène sur le thread principal de Fyne le travail fait en arrière-plan.
+// Les widgets ne doivent être modifiés que depuis ce thread : les goroutines passent par runAsync ou onMain,
+// qui ignorent aussi les résultats arrivant après le remplacement de la vue (contexte annulé).
+package ui
+
+import (
+	"context"
+
+	"fyne.io/fyne/v2"
+)
+
+// runAsync exécute work en arrière-plan puis apply sur le thread Fyne, sauf si ctx est annulé entre-temps
+func runAsync[T any](ctx context.Context, work func(context.Context) (T, error), apply func(T, error)) {
+	go func() {
+		result, err := work(ctx)
+		onMain(ctx, func() { apply(result, err) })
+	}()
+}
+
+// onMain planifie fn sur le thread Fyne ; fn est ignorée si ctx est annulé avant son exécution
+func onMain(ctx context.Context, fn func()) {
+	if ctx.Err() != nil {
+		return
+	}
+	fyne.Do(func() {
+		if ctx.Err() != nil {
+			return
+		}
+		fn()
+	})
+}
@@ -26,19 +26,12 @@ type Suggestion struct {
 	ArtistID int
 }
 
+// SearchArtists filtre les artistes par recherche texte, via le moteur de filtrage commun
 func SearchArtists(query string, artists []models.Artist) []models.Artist {
-	q := strings.TrimSpace(strings.ToLower(query))
-	if q == "" {
+	if strings.TrimSpace(query) == "" {
 		return artists
 	}
-
-	var result []models.Artist
-	for _, a := range artists {
-		if matchArtist(q, a) {
-			result = append(result, a)
-		}
-	}
-	return result
+	return ApplyFilters(artists, FilterCriteria{Query: query})
 }
 
 // BuildSuggestions construit des suggestions multi-types pour la recherche
@@ -73,9 +66,9 @@ func BuildSuggestions(query string, artists []models.Artist) []Suggestion {
 				add(m, SuggestionMember, a.Id)
 			}
 		}
-		for _, loc := range a.Locations {
-			if containsIgnoreCase(loc, q) {
-				add(loc, SuggestionLocation, a.Id)
+		for _, loc := range a.ParsedLocations() {
+			if loc.Matches(q) {
+				add(loc.String(), SuggestionLocation, a.Id)
 			}
 		}
 		if year, ok := firstYearFromString(a.FirstAlbum); ok && strings.Contains(strconv.Itoa(year), q) {
@@ -104,8 +97,8 @@ func matchArtist(q string, a models.Artist) bool {
 			return true
 		}
 	}
-	for _, loc := range a.Locations {
-		if containsIgnoreCase(loc, q) {
+	for _, loc := range a.ParsedLocations() {
+		if loc.Matches(q) {
 			return true
 		}
 	}
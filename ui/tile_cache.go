@@ -0,0 +1,296 @@
+// Package ui - tile_cache.go télécharge les tuiles de carte et les conserve sur disque.
+// Les tuiles sont rangées par z/x/y dans le répertoire de cache, sous une taille maximale :
+// au-delà, les moins récemment utilisées sont supprimées (LRU). Le serveur de tuiles est configurable.
+package ui
+
+import (
+	"Groupie-Tracker/api"
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTileURL est le serveur de tuiles OpenStreetMap ; {z}, {x} et {y} sont remplacés
+	DefaultTileURL = "https://tile.openstreetmap.org/{z}/{x}/{y}.png"
+	// DefaultTileCacheSize est la taille maximale par défaut du cache disque des tuiles
+	DefaultTileCacheSize = 100 << 20
+)
+
+// tileCache est un cache disque de tuiles avec éviction LRU
+type tileCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	lru     *list.List // du plus récent au plus ancien
+	entries map[tileKey]*list.Element
+	total   int64
+}
+
+// tileCacheEntry est un élément de la liste LRU
+type tileCacheEntry struct {
+	key  tileKey
+	size int64
+}
+
+// tileSource associe un serveur de tuiles à son cache
+type tileSource struct {
+	urlTemplate string
+	cache       *tileCache // nil si aucun répertoire de cache n'est disponible
+}
+
+var (
+	tilesMu sync.Mutex
+	tiles   *tileSource
+)
+
+// SetTileServer configure le serveur de tuiles ({z}, {x}, {y} dans urlTemplate) et la taille du cache disque ;
+// une taille nulle ou négative est remplacée par DefaultTileCacheSize, le cache restant toujours borné
+func SetTileServer(urlTemplate string, maxCacheBytes int64) {
+	tilesMu.Lock()
+	defer tilesMu.Unlock()
+	tiles = newTileSource(urlTemplate, maxCacheBytes)
+}
+
+// currentTiles renvoie la source de tuiles, créée avec les valeurs par défaut au premier appel
+func currentTiles() *tileSource {
+	tilesMu.Lock()
+	defer tilesMu.Unlock()
+	if tiles == nil {
+		tiles = newTileSource(DefaultTileURL, DefaultTileCacheSize)
+	}
+	return tiles
+}
+
+// newTileSource crée une source dont le cache est propre au serveur, pour ne pas mélanger deux styles de carte.
+// Les caches des serveurs utilisés précédemment sont supprimés, pour que le total sur disque reste sous maxCacheBytes.
+func newTileSource(urlTemplate string, maxCacheBytes int64) *tileSource {
+	if maxCacheBytes <= 0 {
+		maxCacheBytes = DefaultTileCacheSize
+	}
+	src := &tileSource{urlTemplate: urlTemplate}
+	if dir := assetDir("tiles"); dir != "" {
+		sum := sha256.Sum256([]byte(urlTemplate))
+		name := hex.EncodeToString(sum[:6])
+		removeStaleTileDirs(dir, name)
+		src.cache = newTileCache(filepath.Join(dir, name), maxCacheBytes)
+	}
+	return src
+}
+
+// removeStaleTileDirs supprime de parent les caches de tuiles autres que keep
+func removeStaleTileDirs(parent, keep string) {
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.Name() != keep {
+			os.RemoveAll(filepath.Join(parent, e.Name()))
+		}
+	}
+}
+
+// url renvoie l'URL de la tuile key
+func (s *tileSource) url(key tileKey) string {
+	return strings.NewReplacer(
+		"{z}", strconv.Itoa(key.z),
+		"{x}", strconv.Itoa(key.x),
+		"{y}", strconv.Itoa(key.y),
+	).Replace(s.urlTemplate)
+}
+
+// fetchTile renvoie la tuile z/x/y décodée, depuis le cache disque ou le serveur de tuiles
+func fetchTile(ctx context.Context, z, x, y int) (image.Image, error) {
+	src := currentTiles()
+	key := tileKey{z: z, x: x, y: y}
+
+	if src.cache != nil {
+		if data, ok := src.cache.get(key); ok {
+			if img, _, err := image.Decode(bytes.NewReader(data)); err == nil {
+				return img, nil
+			}
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src.url(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	// Le client ajoute son User-Agent, qui identifie l'application auprès du serveur de tuiles
+	resp, err := api.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tile %d/%d/%d: %s", z, x, y, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if src.cache != nil {
+		src.cache.put(key, data)
+	}
+	return img, nil
+}
+
+// newTileCache ouvre le cache de dir et reconstruit l'ordre LRU d'après les dates de modification
+func newTileCache(dir string, maxBytes int64) *tileCache {
+	c := &tileCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  make(map[tileKey]*list.Element),
+	}
+
+	type found struct {
+		entry   tileCacheEntry
+		modTime time.Time
+	}
+	var files []found
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		key, ok := c.keyForPath(path)
+		if !ok {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, found{tileCacheEntry{key: key, size: info.Size()}, info.ModTime()})
+		return nil
+	})
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+	for _, f := range files {
+		c.entries[f.entry.key] = c.lru.PushBack(f.entry)
+		c.total += f.entry.size
+	}
+
+	c.mu.Lock()
+	c.evict()
+	c.mu.Unlock()
+	return c
+}
+
+// path renvoie le fichier de la tuile key
+func (c *tileCache) path(key tileKey) string {
+	return filepath.Join(c.dir, strconv.Itoa(key.z), strconv.Itoa(key.x), strconv.Itoa(key.y)+".png")
+}
+
+// keyForPath retrouve la tuile correspondant à un fichier du cache
+func (c *tileCache) keyForPath(path string) (tileKey, bool) {
+	rel, err := filepath.Rel(c.dir, path)
+	if err != nil {
+		return tileKey{}, false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) != 3 || !strings.HasSuffix(parts[2], ".png") {
+		return tileKey{}, false
+	}
+	z, errZ := strconv.Atoi(parts[0])
+	x, errX := strconv.Atoi(parts[1])
+	y, errY := strconv.Atoi(strings.TrimSuffix(parts[2], ".png"))
+	if errZ != nil || errX != nil || errY != nil {
+		return tileKey{}, false
+	}
+	return tileKey{z: z, x: x, y: y}, true
+}
+
+// get lit une tuile du cache et la marque comme récemment utilisée
+func (c *tileCache) get(key tileKey) ([]byte, bool) {
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if ok {
+		c.lru.MoveToFront(elem)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.remove(key)
+		return nil, false
+	}
+	// La date de modification conserve l'ordre LRU d'une session à l'autre
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, true
+}
+
+// put écrit une tuile dans le cache puis évince les plus anciennes si la taille maximale est dépassée
+func (c *tileCache) put(key tileKey, data []byte) {
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.total -= elem.Value.(tileCacheEntry).size
+		c.lru.Remove(elem)
+	}
+	c.entries[key] = c.lru.PushFront(tileCacheEntry{key: key, size: int64(len(data))})
+	c.total += int64(len(data))
+	c.evict()
+}
+
+// remove oublie une tuile dont le fichier a disparu
+func (c *tileCache) remove(key tileKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.total -= elem.Value.(tileCacheEntry).size
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+// evict supprime les tuiles les moins récemment utilisées jusqu'à repasser sous maxBytes ; c.mu doit être tenu
+func (c *tileCache) evict() {
+	for c.total > c.maxBytes {
+		oldest := c.lru.Back()
+		if oldest == nil {
+			return
+		}
+		entry := oldest.Value.(tileCacheEntry)
+		c.lru.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.total -= entry.size
+		os.Remove(c.path(entry.key))
+	}
+}